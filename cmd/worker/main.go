@@ -1,11 +1,98 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/brunohfonseca/ratatoskr/internal/config"
+	mongodb "github.com/brunohfonseca/ratatoskr/internal/infrastructure/db/mongodb"
+	redis "github.com/brunohfonseca/ratatoskr/internal/infrastructure/db/redis"
+	"github.com/brunohfonseca/ratatoskr/internal/monitors"
+	"github.com/brunohfonseca/ratatoskr/internal/notifications"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/brunohfonseca/ratatoskr/internal/worker"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	configFile := flag.String("config", "/app/config.yml", "Arquivo de configuração")
+	flag.Parse()
+
 	config.SetupLogs()
 	log.Info().Msg("starting worker")
+
+	_, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatal().Msgf("❌ Erro ao carregar config: %v", err)
+	}
+	cfg := config.Get()
+
+	if cfg.Exec.Enabled {
+		monitors.AllowExec(cfg.Exec.AllowedCommands)
+		log.Warn().Strs("allowed_commands", cfg.Exec.AllowedCommands).Msg("exec monitors enabled")
+	}
+
+	redis.ConnectRedis(cfg.Redis.RedisURL)
+	mongodb.ConnectMongoDB(cfg.Database.MongoURL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	endpoints := repositories.NewEndpointRepository(mongodb.MongoDatabase)
+	incidents := repositories.NewIncidentRepository(mongodb.MongoDatabase)
+	dispatcher := notifications.NewDispatcher(cfg,
+		repositories.NewAlertChannelRepository(mongodb.MongoDatabase),
+		repositories.NewAlertGroupRepository(mongodb.MongoDatabase),
+		repositories.NewTemplateRepository(mongodb.MongoDatabase),
+		redis.RedisClient,
+	)
+
+	history := repositories.NewHistoryRepository(mongodb.MongoDatabase)
+	checks := worker.NewCheckRunner(endpoints, history, incidents, dispatcher, redis.RedisClient)
+	certificates := worker.NewSSLRunner(endpoints, dispatcher, redis.RedisClient)
+	domains := worker.NewDomainRunner(endpoints, monitors.NewRDAPClient(cfg.RDAP.BootstrapURL, cfg.RDAP.BaseURL), dispatcher, redis.RedisClient)
+
+	digests := notifications.NewDigestReporter(
+		repositories.NewDigestRepository(mongodb.MongoDatabase),
+		endpoints,
+		incidents,
+		history,
+		repositories.NewAlertGroupRepository(mongodb.MongoDatabase),
+		dispatcher,
+	)
+
+	worker.Start(ctx,
+		worker.Job{Name: "checks", Interval: 10 * time.Second, Run: checks.RunDue},
+		worker.Job{Name: "ssl", Interval: time.Minute, Run: certificates.RunDue},
+		worker.Job{Name: "domains", Interval: time.Minute, Run: domains.RunDue},
+		worker.Job{Name: "notifications-flush", Interval: time.Minute, Run: dispatcher.FlushPending},
+		worker.Job{Name: "digests", Interval: time.Minute, Run: digests.RunDue},
+	)
+
+	if cfg.Alerts.Telegram.BotEnabled {
+		bot, err := notifications.NewTelegramBot(cfg, endpoints, incidents)
+		if err != nil {
+			log.Error().Msgf("❌ Erro ao iniciar bot do Telegram: %v", err)
+		} else {
+			go bot.Run(ctx)
+		}
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	// Aguardar sinal de parada
+	<-c
+	fmt.Println("") //Quebra de Linha no CTRL+C
+	log.Info().Msg("🛑 Sinal de parada recebido. Finalizando worker...")
+	cancel()
+
+	redis.DisconnectRedis()
+	mongodb.DisconnectMongoDB()
+
+	log.Info().Msg("✅ Worker finalizado com sucesso!")
 }
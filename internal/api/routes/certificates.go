@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/brunohfonseca/ratatoskr/internal/handlers"
+	infra "github.com/brunohfonseca/ratatoskr/internal/infrastructure/db/mongodb"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// setupCertificatesRoutes configura o inventário de certificados dos endpoints
+func setupCertificatesRoutes(api *gin.RouterGroup) {
+	repo := repositories.NewEndpointRepository(infra.MongoDatabase)
+	h := handlers.NewCertificateHandler(repo)
+
+	api.GET("/certificates", h.ListCertificates)
+}
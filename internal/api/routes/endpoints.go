@@ -10,23 +10,27 @@ import (
 // setupServicesRoutes configura rotas relacionadas aos serviços
 func setupServicesRoutes(api *gin.RouterGroup) {
 	repo := repositories.NewEndpointRepository(infra.MongoDatabase)
-	h := handlers.NewEndpointHandler(repo)
+	history := repositories.NewHistoryRepository(infra.MongoDatabase)
+	h := handlers.NewEndpointHandler(repo, history)
 
 	endpoints := api.Group("/endpoints")
 	{
 		// CRUD básico de serviços
 		endpoints.POST("/", h.CreateService)
 		endpoints.GET("/", h.ListServices)
-		endpoints.GET("/:id", handlers.GetService)
-		endpoints.PUT("/:id", handlers.UpdateService)
+		endpoints.GET("/:id", h.GetService)
+		endpoints.PUT("/:id", h.UpdateService)
 		endpoints.DELETE("/:id", handlers.DeleteService)
 
+		// Executa o script de um monitor starlark sem salvar
+		endpoints.POST("/dry-run", h.DryRunScript)
+
 		// Health check e status
-		endpoints.GET("/:id/status", handlers.GetServiceStatus)
+		endpoints.GET("/:id/status", h.GetServiceStatus)
 		endpoints.POST("/:id/health-check", handlers.TriggerHealthCheck)
 
 		// Histórico de health checks
-		endpoints.GET("/:id/history", handlers.GetServiceHistory)
+		endpoints.GET("/:id/history", h.GetServiceHistory)
 		endpoints.GET("/:id/uptime", handlers.GetServiceUptime)
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/brunohfonseca/ratatoskr/internal/handlers"
+	infra "github.com/brunohfonseca/ratatoskr/internal/infrastructure/db/mongodb"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// setupIntegrationsRoutes configura rotas chamadas por serviços externos (Slack, etc.)
+func setupIntegrationsRoutes(api *gin.RouterGroup) {
+	incidents := repositories.NewIncidentRepository(infra.MongoDatabase)
+	endpoints := repositories.NewEndpointRepository(infra.MongoDatabase)
+	slackHandler := handlers.NewSlackHandler(config.Get(), incidents, endpoints)
+
+	integrations := api.Group("/integrations")
+	{
+		// Interactivity Request URL configurada no app do Slack
+		integrations.POST("/slack/interactions", slackHandler.HandleInteraction)
+	}
+}
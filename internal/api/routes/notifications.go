@@ -1,35 +1,56 @@
 package routes
 
 import (
-	"net/http"
-
+	"github.com/brunohfonseca/ratatoskr/internal/handlers"
+	infra "github.com/brunohfonseca/ratatoskr/internal/infrastructure/db/mongodb"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
 	"github.com/gin-gonic/gin"
 )
 
 // setupNotificationsRoutes configura rotas de alertas
 func setupNotificationsRoutes(api *gin.RouterGroup) {
+	channelsHandler := handlers.NewAlertChannelHandler(repositories.NewAlertChannelRepository(infra.MongoDatabase))
+	groupsHandler := handlers.NewAlertGroupHandler(repositories.NewAlertGroupRepository(infra.MongoDatabase))
+	templatesHandler := handlers.NewTemplateHandler(repositories.NewTemplateRepository(infra.MongoDatabase))
+	digestsHandler := handlers.NewDigestHandler(repositories.NewDigestRepository(infra.MongoDatabase))
+
 	alerts := api.Group("/alerts")
 	{
 		// Rotas de canais de alertas
 		channels := alerts.Group("/channels")
 		{
-			channels.GET("/", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{
-					"total":   0,
-					"message": "canais",
-				})
-			})
+			channels.GET("/", channelsHandler.ListChannels)
+			channels.POST("/", channelsHandler.CreateChannel)
+			channels.PUT("/:id", channelsHandler.UpdateChannel)
+			channels.DELETE("/:id", channelsHandler.DeleteChannel)
 		}
 
 		// Rotas de grupos de alertas
 		groups := alerts.Group("/groups")
 		{
-			groups.GET("/", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{
-					"total":   0,
-					"message": "grupos de canais",
-				})
-			})
+			groups.GET("/", groupsHandler.ListGroups)
+			groups.POST("/", groupsHandler.CreateGroup)
+			groups.PUT("/:id", groupsHandler.UpdateGroup)
+			groups.DELETE("/:id", groupsHandler.DeleteGroup)
+		}
+
+		// Rotas de templates de notificação
+		templates := alerts.Group("/templates")
+		{
+			templates.GET("/", templatesHandler.ListTemplates)
+			templates.POST("/", templatesHandler.CreateTemplate)
+			templates.POST("/preview", templatesHandler.PreviewTemplate)
+			templates.PUT("/:id", templatesHandler.UpdateTemplate)
+			templates.DELETE("/:id", templatesHandler.DeleteTemplate)
+		}
+
+		// Rotas de digests (relatórios diários/semanais)
+		digests := alerts.Group("/digests")
+		{
+			digests.GET("/", digestsHandler.ListDigests)
+			digests.POST("/", digestsHandler.CreateDigest)
+			digests.PUT("/:id", digestsHandler.UpdateDigest)
+			digests.DELETE("/:id", digestsHandler.DeleteDigest)
 		}
 	}
 }
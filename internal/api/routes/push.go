@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/brunohfonseca/ratatoskr/internal/handlers"
+	infra "github.com/brunohfonseca/ratatoskr/internal/infrastructure/db/mongodb"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// setupPushRoutes configura as URLs de ping dos monitores heartbeat (o token é o segredo)
+func setupPushRoutes(api *gin.RouterGroup) {
+	repo := repositories.NewEndpointRepository(infra.MongoDatabase)
+	h := handlers.NewPushHandler(repo)
+
+	push := api.Group("/push")
+	{
+		push.Match([]string{"GET", "POST"}, "/:token", h.Ping)
+		push.Match([]string{"GET", "POST"}, "/:token/start", h.Start)
+		push.Match([]string{"GET", "POST"}, "/:token/fail", h.Fail)
+	}
+}
@@ -16,8 +16,14 @@ func setupAPIv1Routes(router *gin.Engine) {
 	{
 		// Services routes - monitoramento de serviços
 		setupServicesRoutes(api)
+		// Certificates routes - inventário de certificados
+		setupCertificatesRoutes(api)
 		// Alerts routes - configuração de alertas
 		setupNotificationsRoutes(api)
+		// Integrations routes - callbacks de Slack e outros serviços
+		setupIntegrationsRoutes(api)
+		// Push routes - pings dos monitores heartbeat
+		setupPushRoutes(api)
 		// Health routes - health check
 		setupHealthRoutes(api)
 	}
@@ -22,6 +22,7 @@ func SetupLogs() {
 type AppConfig struct {
 	Server struct {
 		Port           int      `yaml:"port"`
+		PublicURL      string   `yaml:"public_url"`
 		Debug          bool     `yaml:"debug"`
 		TrustedProxies []string `yaml:"trusted_proxies"`
 		SSL            struct {
@@ -40,14 +41,35 @@ type AppConfig struct {
 	} `yaml:"redis"`
 	Alerts struct {
 		Slack struct {
-			Channel string `yaml:"channel"`
-			Token   string `yaml:"token"`
+			Channel       string `yaml:"channel"`
+			Token         string `yaml:"token"`
+			SigningSecret string `yaml:"signing_secret"`
 		} `yaml:"slack"`
 		Telegram struct {
-			BotToken string `yaml:"bot_token"`
-			ChatID   string `yaml:"chat_id"`
+			BotToken       string  `yaml:"bot_token"`
+			ChatID         string  `yaml:"chat_id"`
+			APIURL         string  `yaml:"api_url"`
+			BotEnabled     bool    `yaml:"bot_enabled"`
+			AllowedChatIDs []int64 `yaml:"allowed_chat_ids"`
 		} `yaml:"telegram"`
+		Email struct {
+			Host     string   `yaml:"host"`
+			Port     int      `yaml:"port"`
+			Username string   `yaml:"username"`
+			Password string   `yaml:"password"`
+			From     string   `yaml:"from"`
+			To       []string `yaml:"to"`
+		} `yaml:"email"`
 	} `yaml:"alerting"`
+	RDAP struct {
+		BootstrapURL string `yaml:"bootstrap_url"` // Default: registro da IANA
+		BaseURL      string `yaml:"base_url"`      // servidor RDAP usado para todos os TLDs (ignora o bootstrap)
+	} `yaml:"rdap"`
+	// Exec habilita monitores exec neste worker; só comandos da allowlist são executados
+	Exec struct {
+		Enabled         bool     `yaml:"enabled"`
+		AllowedCommands []string `yaml:"allowed_commands"` // caminhos ou padrões glob, ex.: /usr/lib/nagios/plugins/check_*
+	} `yaml:"exec"`
 }
 
 func LoadConfig(path string) (*AppConfig, error) {
@@ -0,0 +1,137 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type AuthType string
+
+const (
+	AuthBasic  AuthType = "basic"
+	AuthBearer AuthType = "bearer"
+	AuthAPIKey AuthType = "api_key"
+	AuthOAuth2 AuthType = "oauth2"
+	AuthMTLS   AuthType = "mtls"
+)
+
+// EndpointAuth - Autenticação aplicada pelo executor http em cada request
+type EndpointAuth struct {
+	Type AuthType `bson:"type" json:"type"`
+
+	// basic
+	Username string `bson:"username,omitempty" json:"username,omitempty"`
+	Password string `bson:"password,omitempty" json:"password,omitempty"`
+
+	// bearer
+	Token string `bson:"token,omitempty" json:"token,omitempty"`
+
+	// api_key
+	Key   string `bson:"key,omitempty" json:"key,omitempty"`     // nome do header ou do parâmetro
+	Value string `bson:"value,omitempty" json:"value,omitempty"` // valor da chave
+	In    string `bson:"in,omitempty" json:"in,omitempty"`       // header (default) ou query
+
+	// oauth2 (client credentials)
+	TokenURL     string   `bson:"token_url,omitempty" json:"token_url,omitempty"`
+	ClientID     string   `bson:"client_id,omitempty" json:"client_id,omitempty"`
+	ClientSecret string   `bson:"client_secret,omitempty" json:"client_secret,omitempty"`
+	Scopes       []string `bson:"scopes,omitempty" json:"scopes,omitempty"`
+
+	// mtls (PEM)
+	ClientCert string `bson:"client_cert,omitempty" json:"client_cert,omitempty"`
+	ClientKey  string `bson:"client_key,omitempty" json:"client_key,omitempty"`
+	CACert     string `bson:"ca_cert,omitempty" json:"ca_cert,omitempty"` // opcional, para CAs privadas
+}
+
+// Validate verifica os campos obrigatórios de cada tipo de autenticação
+func (a *EndpointAuth) Validate() error {
+	switch a.Type {
+	case AuthBasic:
+		if a.Username == "" {
+			return errors.New("authentication.username é obrigatório para basic")
+		}
+	case AuthBearer:
+		if a.Token == "" {
+			return errors.New("authentication.token é obrigatório para bearer")
+		}
+	case AuthAPIKey:
+		if a.Key == "" || a.Value == "" {
+			return errors.New("authentication.key e authentication.value são obrigatórios para api_key")
+		}
+		if a.In != "" && a.In != "header" && a.In != "query" {
+			return errors.New("authentication.in deve ser header ou query")
+		}
+	case AuthOAuth2:
+		if a.TokenURL == "" || a.ClientID == "" || a.ClientSecret == "" {
+			return errors.New("authentication.token_url, client_id e client_secret são obrigatórios para oauth2")
+		}
+		if u, err := url.Parse(a.TokenURL); err != nil || u.Host == "" {
+			return errors.New("authentication.token_url inválido")
+		}
+	case AuthMTLS:
+		if a.ClientCert == "" || a.ClientKey == "" {
+			return errors.New("authentication.client_cert e client_key são obrigatórios para mtls")
+		}
+	default:
+		return fmt.Errorf("authentication.type inválido: %s", a.Type)
+	}
+	return nil
+}
+
+// KeepSecrets copia de previous os segredos que vieram mascarados (RedactedSecret) em um update
+func (a *EndpointAuth) KeepSecrets(previous *EndpointAuth) {
+	if previous == nil || previous.Type != a.Type {
+		return
+	}
+	for _, f := range [][2]*string{
+		{&a.Password, &previous.Password},
+		{&a.Token, &previous.Token},
+		{&a.Value, &previous.Value},
+		{&a.ClientSecret, &previous.ClientSecret},
+		{&a.ClientKey, &previous.ClientKey},
+	} {
+		if *f[0] == RedactedSecret {
+			*f[0] = *f[1]
+		}
+	}
+}
+
+// MarshalJSON mascara os segredos para que nunca sejam devolvidos pela API
+func (a EndpointAuth) MarshalJSON() ([]byte, error) {
+	type auth EndpointAuth
+	redacted := auth(a)
+	for _, s := range []*string{&redacted.Password, &redacted.Token, &redacted.Value, &redacted.ClientSecret, &redacted.ClientKey} {
+		if *s != "" {
+			*s = RedactedSecret
+		}
+	}
+	return json.Marshal(redacted)
+}
+
+// redactValues devolve uma cópia de m com todos os valores mascarados (headers e metadata podem levar tokens)
+func redactValues(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return m
+	}
+	redacted := make(map[string]string, len(m))
+	for k, v := range m {
+		if v != "" {
+			v = RedactedSecret
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// keepSecretValues copia de previous os valores que vieram mascarados em um update
+func keepSecretValues(m, previous map[string]string) {
+	for k, v := range m {
+		if v == RedactedSecret {
+			if old, ok := previous[k]; ok {
+				m[k] = old
+			}
+		}
+	}
+}
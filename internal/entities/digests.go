@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// Digest - Relatório periódico (uptime, incidentes, SSL, latência) enviado aos canais de alerta
+type Digest struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Frequency DigestFrequency    `bson:"frequency" json:"frequency"`
+	Time      string             `bson:"time" json:"time"`                           // HH:MM no fuso do digest
+	Weekday   time.Weekday       `bson:"weekday,omitempty" json:"weekday,omitempty"` // apenas weekly, 0 = domingo
+	TimeZone  string             `bson:"timezone,omitempty" json:"timezone,omitempty"`
+
+	// Escopo e destino: sem GroupIDs o relatório inclui todos os grupos
+	GroupIDs   []primitive.ObjectID `bson:"group_ids,omitempty" json:"group_ids,omitempty"`
+	ChannelIDs []primitive.ObjectID `bson:"channel_ids" json:"channel_ids"`
+
+	// Control Fields
+	Enabled    bool      `bson:"enabled" json:"enabled"`
+	LastSentAt time.Time `bson:"last_sent_at,omitempty" json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Validate verifica frequência, horário e fuso horário
+func (d *Digest) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("name é obrigatório")
+	}
+	if d.Frequency != DigestDaily && d.Frequency != DigestWeekly {
+		return fmt.Errorf("frequency deve ser daily ou weekly")
+	}
+	if _, err := time.Parse("15:04", d.Time); err != nil {
+		return fmt.Errorf("time inválido: %q", d.Time)
+	}
+	if d.Weekday < time.Sunday || d.Weekday > time.Saturday {
+		return fmt.Errorf("weekday inválido: %d", d.Weekday)
+	}
+	if _, err := time.LoadLocation(d.TimeZone); err != nil {
+		return fmt.Errorf("timezone inválido: %q", d.TimeZone)
+	}
+	if len(d.ChannelIDs) == 0 {
+		return fmt.Errorf("informe ao menos um canal em channel_ids")
+	}
+	return nil
+}
+
+// Period devolve a duração coberta pelo relatório
+func (d *Digest) Period() time.Duration {
+	if d.Frequency == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// ScheduledAt devolve o último horário agendado do digest até now
+func (d *Digest) ScheduledAt(now time.Time) time.Time {
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	at, _ := time.Parse("15:04", d.Time)
+
+	local := now.In(loc)
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+	if scheduled.After(local) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+	if d.Frequency == DigestWeekly {
+		for scheduled.Weekday() != d.Weekday {
+			scheduled = scheduled.AddDate(0, 0, -1)
+		}
+	}
+	return scheduled
+}
+
+// Due indica se o digest ainda não foi enviado para o último horário agendado
+func (d *Digest) Due(now time.Time) bool {
+	if !d.Enabled {
+		return false
+	}
+	scheduled := d.ScheduledAt(now)
+	// digests recém-criados não enviam o relatório de um horário anterior à criação
+	if d.LastSentAt.IsZero() {
+		return !scheduled.Before(d.CreatedAt)
+	}
+	return d.LastSentAt.Before(scheduled)
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// DomainCheck - Configuração da verificação de expiração do registro do domínio (usada quando CheckDomain está ligado)
+type DomainCheck struct {
+	Interval  int   `bson:"interval,omitempty" json:"interval,omitempty"`     // segundos entre verificações; Default: 24h
+	AlertDays []int `bson:"alert_days,omitempty" json:"alert_days,omitempty"` // Default: 60, 30, 14, 7, 1
+}
+
+// DefaultDomainAlertDays são os limites (dias restantes) que disparam o alerta de expiração do domínio
+var DefaultDomainAlertDays = []int{60, 30, 14, 7, 1}
+
+// CheckInterval devolve o intervalo entre consultas RDAP
+func (c *DomainCheck) CheckInterval() time.Duration {
+	if c == nil || c.Interval <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// AlertThresholds devolve os limites de alerta configurados ou os padrões
+func (c *DomainCheck) AlertThresholds() []int {
+	if c == nil || len(c.AlertDays) == 0 {
+		return DefaultDomainAlertDays
+	}
+	return c.AlertDays
+}
+
+// CrossedThreshold devolve o menor limite já atingido por daysLeft, ou 0 se nenhum foi atingido
+func (c *DomainCheck) CrossedThreshold(daysLeft int) int {
+	return crossedThreshold(c.AlertThresholds(), daysLeft)
+}
+
+// DomainData - Dados do registro do domínio obtidos via RDAP
+type DomainData struct {
+	Domain         string    `bson:"domain,omitempty" json:"domain,omitempty"` // domínio registrável (ex.: example.com.br)
+	Registrar      string    `bson:"registrar,omitempty" json:"registrar,omitempty"`
+	ExpirationDate time.Time `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
+	Expired        bool      `bson:"expired" json:"expired"`
+	DaysLeft       int       `bson:"days_left" json:"days_left"`
+	StatusCodes    []string  `bson:"status_codes,omitempty" json:"status_codes,omitempty"` // códigos EPP, ex.: clientHold, clientTransferProhibited
+	Problems       []string  `bson:"problems,omitempty" json:"problems,omitempty"`
+	CheckedAt      time.Time `bson:"checked_at,omitempty" json:"checked_at,omitempty"`
+
+	// Alert Control (mantidos entre verificações)
+	AlertedThreshold int `bson:"alerted_threshold,omitempty" json:"alerted_threshold,omitempty"` // último limite de dias notificado
+}
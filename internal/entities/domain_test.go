@@ -0,0 +1,26 @@
+package entities
+
+import "testing"
+
+func TestDomainCheckCrossedThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    *DomainCheck
+		daysLeft int
+		want     int
+	}{
+		{"sem limite atingido", nil, 90, 0},
+		{"primeiro limite padrão", nil, 60, 60},
+		{"entre dois limites", nil, 20, 30},
+		{"expirado", nil, 0, 1},
+		{"limites configurados", &DomainCheck{AlertDays: []int{45, 5}}, 20, 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.CrossedThreshold(tt.daysLeft); got != tt.want {
+				t.Errorf("CrossedThreshold(%d) = %d, want %d", tt.daysLeft, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package entities
 
 import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -9,46 +14,97 @@ import (
 type EndpointStatus string
 
 const (
-	StatusOnline  EndpointStatus = "online"
-	StatusOffline EndpointStatus = "offline"
-	StatusUnknown EndpointStatus = "unknown"
+	StatusOnline   EndpointStatus = "online"
+	StatusOffline  EndpointStatus = "offline"
+	StatusDegraded EndpointStatus = "degraded" // funcionando com alertas (ex.: WARNING de plugins exec)
+	StatusUnknown  EndpointStatus = "unknown"
 )
 
 type Endpoint struct {
 	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	Name   string             `bson:"name" json:"name"`
 	Domain string             `bson:"domain" json:"domain"`
+	Type   EndpointType       `bson:"type,omitempty" json:"type,omitempty"` // Default: http
+	Port   int                `bson:"port,omitempty" json:"port,omitempty"`
 
 	// Basic Health Check
 	Endpoint string `bson:"endpoint,omitempty" json:"endpoint,omitempty"` // e.g., "/health"
 	Timeout  int    `bson:"timeout,omitempty" json:"timeout,omitempty"`   // Default: 30s
 	Interval int    `bson:"interval,omitempty" json:"interval,omitempty"` // Default: 5min
 
+	// Response Assertions (http)
+	Assertions []Assertion `bson:"assertions,omitempty" json:"assertions,omitempty"`
+
+	// Monitor Types (configuração específica de cada tipo)
+	TCP       *TCPCheck       `bson:"tcp,omitempty" json:"tcp,omitempty"`
+	DNS       *DNSCheck       `bson:"dns,omitempty" json:"dns,omitempty"`
+	GRPC      *GRPCCheck      `bson:"grpc,omitempty" json:"grpc,omitempty"`
+	WebSocket *WebSocketCheck `bson:"websocket,omitempty" json:"websocket,omitempty"`
+	Mail      *MailCheck      `bson:"mail,omitempty" json:"mail,omitempty"`         // smtp, imap e pop3
+	Database  *DatabaseCheck  `bson:"database,omitempty" json:"database,omitempty"` // postgres, mysql, mongodb e redis
+	Exec      *ExecCheck      `bson:"exec,omitempty" json:"exec,omitempty"`
+	Starlark  *StarlarkCheck  `bson:"starlark,omitempty" json:"starlark,omitempty"`
+	Heartbeat *HeartbeatCheck `bson:"heartbeat,omitempty" json:"heartbeat,omitempty"`
+	Scenario  *ScenarioCheck  `bson:"scenario,omitempty" json:"scenario,omitempty"`
+
 	// SSL Configuration
-	CheckSSL bool `bson:"check_ssl" json:"check_ssl"`
-	SSLData  struct {
-		ExpirationDate time.Time `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
-		Expired        bool      `bson:"expired" json:"expired"`
-		DaysLeft       int       `bson:"days_left" json:"days_left"`
-		Issuer         string    `bson:"issuer,omitempty" json:"issuer,omitempty"`
-	} `bson:"ssl_data,omitempty" json:"ssl_data,omitempty"`
+	CheckSSL   bool                `bson:"check_ssl" json:"check_ssl"`
+	SSL        *SSLCheck           `bson:"ssl,omitempty" json:"ssl,omitempty"`
+	SSLData    SSLData             `bson:"ssl_data,omitempty" json:"ssl_data,omitempty"`
+	SSLHistory []CertificateRecord `bson:"ssl_history,omitempty" json:"ssl_history,omitempty"` // certificados já servidos, do mais antigo ao mais recente
+
+	// Domain Expiry (RDAP)
+	CheckDomain bool         `bson:"check_domain" json:"check_domain"`
+	DomainCheck *DomainCheck `bson:"domain_check,omitempty" json:"domain_check,omitempty"`
+	DomainData  DomainData   `bson:"domain_data,omitempty" json:"domain_data,omitempty"`
 
 	// Current Status
-	Status       EndpointStatus `bson:"status" json:"status"`
-	ResponseTime int            `bson:"response_time,omitempty" json:"response_time,omitempty"`
-	ErrorMessage string         `bson:"error_message,omitempty" json:"error_message,omitempty"`
+	Status       EndpointStatus  `bson:"status" json:"status"`
+	ResponseTime int             `bson:"response_time,omitempty" json:"response_time,omitempty"` // ms
+	ErrorMessage string          `bson:"error_message,omitempty" json:"error_message,omitempty"`
+	DNSAnswers   []string        `bson:"dns_answers,omitempty" json:"dns_answers,omitempty"` // última resposta observada (dns)
+	Pings        *HeartbeatState `bson:"pings,omitempty" json:"pings,omitempty"`             // pings recebidos (heartbeat)
 
 	// Alert Groups (referência aos grupos de alerta)
 	AlertGroupIDs []primitive.ObjectID `bson:"alert_group_ids,omitempty" json:"alert_group_ids,omitempty"`
 
 	// Authentication
-	Authentication interface{} `bson:"authentication,omitempty" json:"authentication,omitempty"`
+	Authentication *EndpointAuth `bson:"authentication,omitempty" json:"authentication,omitempty"`
 
 	// Control Fields
-	Enabled   bool      `bson:"enabled" json:"enabled"`
-	LastCheck time.Time `bson:"last_check,omitempty" json:"last_check,omitempty"`
-	CreatedAt time.Time `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	Enabled    bool      `bson:"enabled" json:"enabled"`
+	MutedUntil time.Time `bson:"muted_until,omitempty" json:"muted_until,omitempty"`
+	LastCheck  time.Time `bson:"last_check,omitempty" json:"last_check,omitempty"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// MonitorType devolve o tipo do monitor, assumindo http para endpoints antigos sem type
+func (e *Endpoint) MonitorType() EndpointType {
+	if e.Type == "" {
+		return TypeHTTP
+	}
+	return e.Type
+}
+
+// URL monta a URL verificada pelo health check a partir do Domain, Port e Endpoint
+func (e *Endpoint) URL() string {
+	domain := strings.TrimRight(e.Domain, "/")
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	if e.Port > 0 && (e.MonitorType() == TypeHTTP || e.MonitorType() == TypeWebSocket) {
+		if u, err := url.Parse(domain); err == nil && u.Port() == "" {
+			u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(e.Port))
+			domain = u.String()
+		}
+	}
+	return domain + e.Endpoint
+}
+
+// IsMuted indica se os alertas do endpoint estão silenciados no momento informado
+func (e *Endpoint) IsMuted(now time.Time) bool {
+	return !e.MutedUntil.IsZero() && now.Before(e.MutedUntil)
 }
 
 // EndpointHealthHistory - Para manter histórico de checks
@@ -56,7 +112,43 @@ type EndpointHealthHistory struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	EndPointID   primitive.ObjectID `bson:"endpoint_id" json:"endpoint_id"`
 	Status       EndpointStatus     `bson:"status" json:"status"`
-	ResponseTime time.Duration      `bson:"response_time,omitempty" json:"response_time,omitempty"`
+	ResponseTime time.Duration      `bson:"response_time,omitempty" json:"-"`               // na API sai como response_time_ms
+	Timings      *RequestTimings    `bson:"timings,omitempty" json:"timings,omitempty"`     // fases do request (http)
+	GRPCCode     string             `bson:"grpc_code,omitempty" json:"grpc_code,omitempty"` // código de status da chamada (grpc), ex.: OK, Unavailable
+	Output       string             `bson:"output,omitempty" json:"output,omitempty"`       // primeira linha da saída do plugin (exec) ou banner (smtp, imap, pop3)
 	ErrorMessage string             `bson:"error_message,omitempty" json:"error_message,omitempty"`
+	Metrics      map[string]float64 `bson:"metrics,omitempty" json:"metrics,omitempty"` // ex.: connect_ms
+	Assertions   []AssertionResult  `bson:"assertions,omitempty" json:"assertions,omitempty"`
+	Steps        []StepResult       `bson:"steps,omitempty" json:"steps,omitempty"` // passos do cenário (scenario)
 	CheckedAt    time.Time          `bson:"checked_at" json:"checked_at" ttl:"120d"`
 }
+
+// MarshalJSON expõe o tempo de resposta em ms, a mesma unidade do status e das fases do request
+func (h EndpointHealthHistory) MarshalJSON() ([]byte, error) {
+	type history EndpointHealthHistory
+	return json.Marshal(struct {
+		history
+		ResponseTimeMS float64 `json:"response_time_ms,omitempty"`
+	}{history(h), durationMS(h.ResponseTime)})
+}
+
+// durationMS converte a duração para ms com casas decimais
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// EndpointStats - Agregado do histórico de checks de um endpoint em um período
+type EndpointStats struct {
+	EndpointID       primitive.ObjectID `bson:"endpoint_id" json:"endpoint_id"`
+	TotalChecks      int                `bson:"total_checks" json:"total_checks"`
+	SuccessfulChecks int                `bson:"successful_checks" json:"successful_checks"`
+	AvgResponseTime  time.Duration      `bson:"avg_response_time" json:"avg_response_time"`
+}
+
+// Uptime devolve o percentual de checks com sucesso (100 quando não há checks)
+func (s EndpointStats) Uptime() float64 {
+	if s.TotalChecks == 0 {
+		return 100
+	}
+	return float64(s.SuccessfulChecks) / float64(s.TotalChecks) * 100
+}
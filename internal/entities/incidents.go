@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IncidentStatus string
+
+const (
+	IncidentOpen         IncidentStatus = "open"
+	IncidentAcknowledged IncidentStatus = "acknowledged"
+	IncidentResolved     IncidentStatus = "resolved"
+)
+
+// Incident - Representa uma indisponibilidade de um endpoint, do primeiro erro até a resolução
+type Incident struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EndpointID   primitive.ObjectID `bson:"endpoint_id" json:"endpoint_id"`
+	EndpointName string             `bson:"endpoint_name" json:"endpoint_name"`
+	Status       IncidentStatus     `bson:"status" json:"status"`
+	ErrorMessage string             `bson:"error_message,omitempty" json:"error_message,omitempty"`
+
+	// Acknowledgement / Resolution
+	AcknowledgedBy string    `bson:"acknowledged_by,omitempty" json:"acknowledged_by,omitempty"`
+	AcknowledgedAt time.Time `bson:"acknowledged_at,omitempty" json:"acknowledged_at,omitempty"`
+	ResolvedBy     string    `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+	ResolvedAt     time.Time `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+
+	// Control Fields
+	StartedAt time.Time `bson:"started_at" json:"started_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,235 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EndpointType string
+
+const (
+	TypeHTTP      EndpointType = "http"
+	TypeTCP       EndpointType = "tcp"
+	TypeDNS       EndpointType = "dns"
+	TypeGRPC      EndpointType = "grpc"
+	TypeWebSocket EndpointType = "websocket"
+
+	TypeSMTP EndpointType = "smtp"
+	TypeIMAP EndpointType = "imap"
+	TypePOP3 EndpointType = "pop3"
+
+	TypePostgres EndpointType = "postgres"
+	TypeMySQL    EndpointType = "mysql"
+	TypeMongoDB  EndpointType = "mongodb"
+	TypeRedis    EndpointType = "redis"
+
+	TypeExec     EndpointType = "exec"
+	TypeStarlark EndpointType = "starlark"
+
+	TypeHeartbeat EndpointType = "heartbeat"
+	TypeScenario  EndpointType = "scenario"
+)
+
+// EndpointTypes lista os tipos de monitor suportados
+var EndpointTypes = []EndpointType{TypeHTTP, TypeTCP, TypeDNS, TypeGRPC, TypeWebSocket, TypeSMTP, TypeIMAP, TypePOP3, TypePostgres, TypeMySQL, TypeMongoDB, TypeRedis, TypeExec, TypeStarlark, TypeHeartbeat, TypeScenario}
+
+// IsValid indica se o tipo de monitor é conhecido
+func (t EndpointType) IsValid() bool {
+	for _, et := range EndpointTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// TCPCheck - Conecta em Domain:Port e, opcionalmente, envia um payload e valida a resposta (banner)
+type TCPCheck struct {
+	Send        string `bson:"send,omitempty" json:"send,omitempty"`                 // aceita escapes, ex.: "PING\r\n"
+	Expect      string `bson:"expect,omitempty" json:"expect,omitempty"`             // substring esperada na resposta
+	ExpectRegex string `bson:"expect_regex,omitempty" json:"expect_regex,omitempty"` // regex esperada na resposta
+}
+
+// DNSCheck - Consulta um registro de Domain no resolver escolhido e valida as respostas
+type DNSCheck struct {
+	RecordType      string   `bson:"record_type" json:"record_type"`                                 // A, AAAA, CNAME, MX, TXT, NS, SRV, CAA
+	Resolver        string   `bson:"resolver,omitempty" json:"resolver,omitempty"`                   // ex.: 1.1.1.1:53 ou https://cloudflare-dns.com/dns-query
+	Protocol        string   `bson:"protocol,omitempty" json:"protocol,omitempty"`                   // udp (default), tcp ou doh
+	Expected        []string `bson:"expected,omitempty" json:"expected,omitempty"`                   // conjunto exato de respostas esperadas
+	ExpectRegex     string   `bson:"expect_regex,omitempty" json:"expect_regex,omitempty"`           // regex que todas as respostas devem satisfazer
+	MaxResponseTime int      `bson:"max_response_time,omitempty" json:"max_response_time,omitempty"` // ms; acima disso o check falha
+	AlertOnChange   bool     `bson:"alert_on_change" json:"alert_on_change"`                         // notifica quando as respostas mudam
+}
+
+// GRPCCheck - Chama grpc.health.v1.Health/Check em Domain:Port; SERVING é online.
+// Para mTLS use Authentication do tipo mtls (implica TLS).
+type GRPCCheck struct {
+	Service            string            `bson:"service,omitempty" json:"service,omitempty"`                           // serviço consultado; vazio = servidor inteiro
+	TLS                bool              `bson:"tls,omitempty" json:"tls,omitempty"`                                   // Default: plaintext
+	ServerName         string            `bson:"server_name,omitempty" json:"server_name,omitempty"`                   // SNI e hostname validado; Default: Domain
+	InsecureSkipVerify bool              `bson:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"` // não valida o certificado do servidor
+	Metadata           map[string]string `bson:"metadata,omitempty" json:"metadata,omitempty"`                         // headers enviados na chamada
+}
+
+// KeepSecrets mantém os valores de metadata que vieram mascarados em um update
+func (c *GRPCCheck) KeepSecrets(previous *GRPCCheck) {
+	if previous != nil {
+		keepSecretValues(c.Metadata, previous.Metadata)
+	}
+}
+
+// MarshalJSON mascara os valores de metadata, que costumam levar tokens
+func (c GRPCCheck) MarshalJSON() ([]byte, error) {
+	type check GRPCCheck
+	redacted := check(c)
+	redacted.Metadata = redactValues(c.Metadata)
+	return json.Marshal(redacted)
+}
+
+// WebSocketCheck - Faz o upgrade na URL do endpoint (http vira ws, https vira wss), opcionalmente envia
+// uma mensagem e espera uma mensagem que satisfaça Expect (assertion body ou json) dentro do timeout
+type WebSocketCheck struct {
+	Headers        map[string]string `bson:"headers,omitempty" json:"headers,omitempty"`
+	Subprotocols   []string          `bson:"subprotocols,omitempty" json:"subprotocols,omitempty"`
+	Send           string            `bson:"send,omitempty" json:"send,omitempty"`                       // mensagem de texto enviada após o upgrade
+	Expect         *Assertion        `bson:"expect,omitempty" json:"expect,omitempty"`                   // body (contains/matches) ou json (target = caminho gjson)
+	ReceiveTimeout int               `bson:"receive_timeout,omitempty" json:"receive_timeout,omitempty"` // ms para receber a mensagem esperada; Default: Timeout do endpoint
+}
+
+// KeepSecrets mantém os valores de headers que vieram mascarados em um update
+func (c *WebSocketCheck) KeepSecrets(previous *WebSocketCheck) {
+	if previous != nil {
+		keepSecretValues(c.Headers, previous.Headers)
+	}
+}
+
+// MarshalJSON mascara os valores de headers, que costumam levar tokens
+func (c WebSocketCheck) MarshalJSON() ([]byte, error) {
+	type check WebSocketCheck
+	redacted := check(c)
+	redacted.Headers = redactValues(c.Headers)
+	return json.Marshal(redacted)
+}
+
+// MailCheck - Conecta no servidor smtp, imap ou pop3, lê o banner, lista as capabilities (EHLO, CAPABILITY
+// ou CAPA) e, com Login, autentica com Authentication do tipo basic. As capabilities são comparadas sem
+// diferenciar maiúsculas e os mecanismos de autenticação usam a forma AUTH=MECANISMO nos três protocolos.
+type MailCheck struct {
+	TLS          bool     `bson:"tls,omitempty" json:"tls,omitempty"`                   // TLS implícito (465, 993, 995)
+	StartTLS     bool     `bson:"starttls,omitempty" json:"starttls,omitempty"`         // negocia STARTTLS/STLS antes do login
+	Capabilities []string `bson:"capabilities,omitempty" json:"capabilities,omitempty"` // esperadas, ex.: STARTTLS, AUTH=PLAIN
+	Login        bool     `bson:"login,omitempty" json:"login,omitempty"`               // exige TLS ou StartTLS
+	Hostname     string   `bson:"hostname,omitempty" json:"hostname,omitempty"`         // nome enviado no EHLO; Default: ratatoskr
+}
+
+// DatabaseCheck - Conecta em Domain:Port (postgres, mysql, mongodb ou redis), executa uma consulta leve e,
+// opcionalmente, valida o valor escalar retornado. As credenciais vêm de Authentication do tipo basic
+// (no redis sem ACL, use o usuário default). A consulta é somente leitura: no SQL ela roda em uma transação
+// READ ONLY e no redis e mongodb apenas comandos de leitura são aceitos.
+type DatabaseCheck struct {
+	Database string `bson:"database,omitempty" json:"database,omitempty"` // nome do banco; no redis, o número do DB
+	Query    string `bson:"query,omitempty" json:"query,omitempty"`       // uma única consulta; Default: SELECT 1, {"ping": 1} (mongodb) ou PING (redis)
+	Expect   string `bson:"expect,omitempty" json:"expect,omitempty"`     // valor esperado da primeira coluna da primeira linha (ou da resposta)
+	Operator string `bson:"operator,omitempty" json:"operator,omitempty"` // mesmos operadores das assertions; Default: eq
+	TLS      bool   `bson:"tls,omitempty" json:"tls,omitempty"`
+}
+
+// ExecCheck - Executa um plugin no padrão do Nagios (check_*) no worker. O comando precisa estar na
+// allowlist do worker; códigos de saída 0/1/2/3 viram online/degraded/offline/unknown.
+type ExecCheck struct {
+	Command string            `bson:"command" json:"command"` // caminho do executável, ex.: /usr/lib/nagios/plugins/check_disk
+	Args    []string          `bson:"args,omitempty" json:"args,omitempty"`
+	Env     map[string]string `bson:"env,omitempty" json:"env,omitempty"`
+}
+
+// StarlarkCheck - Script Starlark com a lógica do check. O script define check(), que devolve o status
+// ("online", "degraded", "offline" ou "unknown") ou um dict com status, message e metrics.
+// Módulos disponíveis: http, dns, tcp e json; print() vai para os logs do dry-run.
+type StarlarkCheck struct {
+	Script   string `bson:"script" json:"script"`
+	MaxSteps uint64 `bson:"max_steps,omitempty" json:"max_steps,omitempty"` // limite de CPU em passos de execução; Default: 10 milhões
+}
+
+// HeartbeatCheck - Monitor passivo: o job chama /api/v1/push/:token e o worker alerta se o ping atrasar
+type HeartbeatCheck struct {
+	Token    string `bson:"token" json:"token"`                           // gerado na criação do endpoint
+	Period   int    `bson:"period,omitempty" json:"period,omitempty"`     // segundos entre pings esperados
+	Cron     string `bson:"cron,omitempty" json:"cron,omitempty"`         // alternativa ao period, ex.: "0 3 * * *"
+	TimeZone string `bson:"timezone,omitempty" json:"timezone,omitempty"` // timezone do cron; Default: UTC
+	Grace    int    `bson:"grace,omitempty" json:"grace,omitempty"`       // segundos de tolerância após o horário esperado
+}
+
+// HeartbeatState - Últimos pings recebidos de um monitor heartbeat
+type HeartbeatState struct {
+	LastSuccess time.Time `bson:"last_success,omitempty" json:"last_success,omitempty"`
+	LastStart   time.Time `bson:"last_start,omitempty" json:"last_start,omitempty"`
+	LastFail    time.Time `bson:"last_fail,omitempty" json:"last_fail,omitempty"`
+	ExitCode    int       `bson:"exit_code" json:"exit_code"`
+	Message     string    `bson:"message,omitempty" json:"message,omitempty"`
+}
+
+// Assertion - Validação aplicada à resposta de um check http
+type Assertion struct {
+	Type     string `bson:"type" json:"type"`                             // body, json, header, size, latency
+	Target   string `bson:"target,omitempty" json:"target,omitempty"`     // caminho gjson (json) ou nome do header (header)
+	Operator string `bson:"operator,omitempty" json:"operator,omitempty"` // eq, ne, contains, not_contains, matches, lt, lte, gt, gte, exists, not_exists
+	Value    string `bson:"value,omitempty" json:"value,omitempty"`       // size em bytes, latency em ms
+}
+
+// AssertionResult - Resultado de uma assertion em um check
+type AssertionResult struct {
+	Assertion Assertion `bson:"assertion" json:"assertion"`
+	Passed    bool      `bson:"passed" json:"passed"`
+	Actual    string    `bson:"actual,omitempty" json:"actual,omitempty"`
+	Message   string    `bson:"message,omitempty" json:"message,omitempty"`
+}
+
+// ScenarioCheck - Sequência de requests http executada como um único check (ex.: login + chamada autenticada)
+type ScenarioCheck struct {
+	Steps []ScenarioStep `bson:"steps" json:"steps"`
+}
+
+// ScenarioStep - Um request do cenário. URL, Headers e Body aceitam variáveis extraídas
+// dos passos anteriores no formato {{ nome }}. A Authentication do endpoint só é enviada
+// aos passos no mesmo host e porta do endpoint.
+type ScenarioStep struct {
+	Name         string            `bson:"name" json:"name"`
+	Method       string            `bson:"method,omitempty" json:"method,omitempty"` // Default: GET
+	URL          string            `bson:"url" json:"url"`                           // absoluta ou path relativo ao Domain
+	Headers      map[string]string `bson:"headers,omitempty" json:"headers,omitempty"`
+	Body         string            `bson:"body,omitempty" json:"body,omitempty"`
+	ExpectStatus int               `bson:"expect_status,omitempty" json:"expect_status,omitempty"` // Default: qualquer status < 400
+	Extract      map[string]string `bson:"extract,omitempty" json:"extract,omitempty"`             // variável -> caminho gjson ou "header:Nome"
+	Assertions   []Assertion       `bson:"assertions,omitempty" json:"assertions,omitempty"`
+}
+
+// StepResult - Resultado de um passo do cenário no histórico
+type StepResult struct {
+	Name         string          `bson:"name" json:"name"`
+	StatusCode   int             `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	ResponseTime time.Duration   `bson:"response_time" json:"-"` // na API sai como response_time_ms
+	Timings      *RequestTimings `bson:"timings,omitempty" json:"timings,omitempty"`
+	Passed       bool            `bson:"passed" json:"passed"`
+	ErrorMessage string          `bson:"error_message,omitempty" json:"error_message,omitempty"`
+}
+
+// MarshalJSON expõe o tempo de resposta do passo em ms, como no histórico
+func (r StepResult) MarshalJSON() ([]byte, error) {
+	type step StepResult
+	return json.Marshal(struct {
+		step
+		ResponseTimeMS float64 `json:"response_time_ms"`
+	}{step(r), durationMS(r.ResponseTime)})
+}
+
+// RequestTimings - Fases de um request http medidas com httptrace, em milissegundos.
+// Os checks abrem uma conexão por request; dns, connect e tls só saem zerados quando ConnReused.
+type RequestTimings struct {
+	DNSLookup       float64 `bson:"dns_ms" json:"dns_ms"`
+	TCPConnect      float64 `bson:"connect_ms" json:"connect_ms"`
+	TLSHandshake    float64 `bson:"tls_ms" json:"tls_ms"`
+	TimeToFirstByte float64 `bson:"ttfb_ms" json:"ttfb_ms"`         // do request enviado ao primeiro byte da resposta (tempo do backend)
+	ContentTransfer float64 `bson:"transfer_ms" json:"transfer_ms"` // do primeiro ao último byte do body
+	Total           float64 `bson:"total_ms" json:"total_ms"`
+	ConnReused      bool    `bson:"conn_reused,omitempty" json:"conn_reused,omitempty"`
+}
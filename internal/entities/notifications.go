@@ -1,6 +1,45 @@
 package entities
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventType string
+
+const (
+	EventDown           EventType = "down"
+	EventUp             EventType = "up"
+	EventSSLExpiring    EventType = "ssl_expiring"
+	EventFlapping       EventType = "flapping"
+	EventReminder       EventType = "reminder"
+	EventDNSChanged     EventType = "dns_changed"
+	EventSSLInvalid     EventType = "ssl_invalid"
+	EventSSLRevoked     EventType = "ssl_revoked"
+	EventSSLChanged     EventType = "ssl_changed"
+	EventDomainExpiring EventType = "domain_expiring"
+)
+
+// EventTypes lista os tipos de evento que aceitam template
+var EventTypes = []EventType{EventDown, EventUp, EventSSLExpiring, EventFlapping, EventReminder, EventDNSChanged, EventSSLInvalid, EventSSLRevoked, EventSSLChanged, EventDomainExpiring}
+
+// IsValid indica se o tipo de evento é conhecido
+func (t EventType) IsValid() bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCritical indica se o evento deve ser entregue mesmo durante o horário silencioso
+func (t EventType) IsCritical() bool {
+	return t == EventDown || t == EventSSLInvalid || t == EventSSLRevoked
+}
 
 type AlertChannel struct {
 	ID      primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
@@ -8,6 +47,97 @@ type AlertChannel struct {
 	Name    string                 `bson:"name" json:"name" index:"unique"`
 	Config  map[string]interface{} `bson:"config" json:"config"`
 	Enabled bool                   `bson:"enabled" json:"enabled"`
+
+	// Delivery Control
+	QuietHours *QuietHours `bson:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+	RateLimit  *RateLimit  `bson:"rate_limit,omitempty" json:"rate_limit,omitempty"`
+}
+
+// RedactedSecret substitui os segredos nas respostas da API; enviado de volta em um update, mantém o valor salvo
+const RedactedSecret = "********"
+
+// ChannelSecretKeys são as chaves de AlertChannel.Config que guardam credenciais
+var ChannelSecretKeys = []string{"token", "bot_token", "webhook_url", "password"}
+
+// KeepSecrets copia de previous os segredos do Config que vieram mascarados (RedactedSecret) em um update
+func (ch *AlertChannel) KeepSecrets(previous *AlertChannel) {
+	if previous == nil || previous.Type != ch.Type {
+		return
+	}
+	for _, k := range ChannelSecretKeys {
+		if v, _ := ch.Config[k].(string); v == RedactedSecret {
+			ch.Config[k] = previous.Config[k]
+		}
+	}
+}
+
+// MarshalJSON mascara os segredos do Config para que nunca sejam devolvidos pela API
+func (ch AlertChannel) MarshalJSON() ([]byte, error) {
+	type channel AlertChannel
+	redacted := channel(ch)
+	if len(ch.Config) > 0 {
+		redacted.Config = make(map[string]interface{}, len(ch.Config))
+		for k, v := range ch.Config {
+			redacted.Config[k] = v
+		}
+		for _, k := range ChannelSecretKeys {
+			if v, ok := redacted.Config[k]; ok && v != "" {
+				redacted.Config[k] = RedactedSecret
+			}
+		}
+	}
+	return json.Marshal(redacted)
+}
+
+// QuietHours - Janela (HH:MM) em que eventos não críticos são retidos e entregues depois como resumo.
+// A janela pode atravessar a meia-noite, ex.: 22:00 - 07:00.
+type QuietHours struct {
+	Start    string `bson:"start" json:"start"`
+	End      string `bson:"end" json:"end"`
+	TimeZone string `bson:"timezone,omitempty" json:"timezone,omitempty"` // Default: UTC
+}
+
+// RateLimit - Máximo de mensagens por canal; zero desabilita o limite da janela
+type RateLimit struct {
+	PerMinute int `bson:"per_minute,omitempty" json:"per_minute,omitempty"`
+	PerHour   int `bson:"per_hour,omitempty" json:"per_hour,omitempty"`
+}
+
+// Validate verifica o formato dos horários e o fuso horário
+func (q *QuietHours) Validate() error {
+	if _, err := time.Parse("15:04", q.Start); err != nil {
+		return fmt.Errorf("quiet_hours.start inválido: %q", q.Start)
+	}
+	if _, err := time.Parse("15:04", q.End); err != nil {
+		return fmt.Errorf("quiet_hours.end inválido: %q", q.End)
+	}
+	if _, err := time.LoadLocation(q.TimeZone); err != nil {
+		return fmt.Errorf("quiet_hours.timezone inválido: %q", q.TimeZone)
+	}
+	return nil
+}
+
+// Active indica se o horário informado está dentro da janela silenciosa
+func (q *QuietHours) Active(now time.Time) bool {
+	loc, err := time.LoadLocation(q.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, errStart := time.Parse("15:04", q.Start)
+	end, errEnd := time.Parse("15:04", q.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	local := now.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return minutes >= from && minutes < to
+	}
+	return minutes >= from || minutes < to
 }
 
 type AlertGroup struct {
@@ -16,3 +146,15 @@ type AlertGroup struct {
 	ChannelIDs []primitive.ObjectID `bson:"channel_ids" json:"channel_ids"`
 	Enabled    bool                 `bson:"enabled" json:"enabled"`
 }
+
+// NotificationTemplate - Template (text/template) de mensagem para um tipo de evento.
+// Pode pertencer a um canal (ChannelID) ou a um grupo (GroupID); o template do canal tem precedência.
+type NotificationTemplate struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ChannelID *primitive.ObjectID `bson:"channel_id,omitempty" json:"channel_id,omitempty"`
+	GroupID   *primitive.ObjectID `bson:"group_id,omitempty" json:"group_id,omitempty"`
+	EventType EventType           `bson:"event_type" json:"event_type"`
+	Body      string              `bson:"body" json:"body"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+}
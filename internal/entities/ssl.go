@@ -0,0 +1,245 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SSLStatus string
+
+const (
+	SSLValid    SSLStatus = "valid"
+	SSLExpiring SSLStatus = "expiring"
+	SSLInvalid  SSLStatus = "invalid"
+	SSLRevoked  SSLStatus = "revoked"
+)
+
+type RevocationState string
+
+const (
+	RevocationGood    RevocationState = "good"
+	RevocationRevoked RevocationState = "revoked"
+	RevocationUnknown RevocationState = "unknown"
+)
+
+// SSLCheck - Configuração da verificação de certificado (usada quando CheckSSL está ligado)
+type SSLCheck struct {
+	Port         int    `bson:"port,omitempty" json:"port,omitempty"`                   // Default: Port do endpoint, a porta do starttls ou 443
+	StartTLS     string `bson:"starttls,omitempty" json:"starttls,omitempty"`           // smtp, imap, pop3, ftp, ldap, xmpp ou postgres
+	ServerName   string `bson:"server_name,omitempty" json:"server_name,omitempty"`     // SNI e hostname validado; Default: Domain
+	RootCAs      string `bson:"root_cas,omitempty" json:"root_cas,omitempty"`           // PEM; substitui as raízes do sistema
+	ExpiringDays int    `bson:"expiring_days,omitempty" json:"expiring_days,omitempty"` // Default: 30; status expiring a partir daqui
+	InvalidDays  int    `bson:"invalid_days,omitempty" json:"invalid_days,omitempty"`   // Default: 0; status invalid a partir daqui (0 = só depois de expirar)
+	Interval     int    `bson:"interval,omitempty" json:"interval,omitempty"`           // segundos entre verificações; Default: 12h
+	AlertDays    []int  `bson:"alert_days,omitempty" json:"alert_days,omitempty"`       // Default: 30, 14, 7, 1
+
+	// Revocation
+	SkipRevocation bool   `bson:"skip_revocation,omitempty" json:"skip_revocation,omitempty"`
+	OCSPResponder  string `bson:"ocsp_responder,omitempty" json:"ocsp_responder,omitempty"` // substitui o responder do certificado (ex.: responder local)
+
+	// Pinning
+	Pins          []string `bson:"pins,omitempty" json:"pins,omitempty"`                       // SHA-256 (hex) do certificado ou da chave pública (SPKI) de algum certificado da cadeia
+	AlertOnChange bool     `bson:"alert_on_change,omitempty" json:"alert_on_change,omitempty"` // alerta a cada troca de certificado; troca de emissor sempre alerta
+}
+
+// DefaultSSLAlertDays são os limites (dias restantes) que disparam o alerta de expiração
+var DefaultSSLAlertDays = []int{30, 14, 7, 1}
+
+// CheckInterval devolve o intervalo entre verificações de certificado
+func (c *SSLCheck) CheckInterval() time.Duration {
+	if c == nil || c.Interval <= 0 {
+		return 12 * time.Hour
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// AlertThresholds devolve os limites de alerta configurados ou os padrões
+func (c *SSLCheck) AlertThresholds() []int {
+	if c == nil || len(c.AlertDays) == 0 {
+		return DefaultSSLAlertDays
+	}
+	return c.AlertDays
+}
+
+// CrossedThreshold devolve o menor limite já atingido por daysLeft, ou 0 se nenhum foi atingido
+func (c *SSLCheck) CrossedThreshold(daysLeft int) int {
+	return crossedThreshold(c.AlertThresholds(), daysLeft)
+}
+
+func crossedThreshold(thresholds []int, daysLeft int) int {
+	crossed := 0
+	for _, t := range thresholds {
+		if daysLeft <= t && (crossed == 0 || t < crossed) {
+			crossed = t
+		}
+	}
+	return crossed
+}
+
+// ExpiringThreshold devolve o limite em dias para o status expiring
+func (c *SSLCheck) ExpiringThreshold() int {
+	if c == nil || c.ExpiringDays <= 0 {
+		return 30
+	}
+	return c.ExpiringDays
+}
+
+// InvalidThreshold devolve o limite em dias para o status invalid
+func (c *SSLCheck) InvalidThreshold() int {
+	if c == nil || c.InvalidDays < 0 {
+		return 0
+	}
+	return c.InvalidDays
+}
+
+// SSLData - Relatório TLS do último check de certificado
+type SSLData struct {
+	Status         SSLStatus `bson:"status,omitempty" json:"status,omitempty"`
+	ExpirationDate time.Time `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
+	Expired        bool      `bson:"expired" json:"expired"`
+	DaysLeft       int       `bson:"days_left" json:"days_left"`
+	Issuer         string    `bson:"issuer,omitempty" json:"issuer,omitempty"`
+
+	// Leaf Certificate
+	Subject            string    `bson:"subject,omitempty" json:"subject,omitempty"`
+	SANs               []string  `bson:"sans,omitempty" json:"sans,omitempty"`
+	SerialNumber       string    `bson:"serial_number,omitempty" json:"serial_number,omitempty"`
+	NotBefore          time.Time `bson:"not_before,omitempty" json:"not_before,omitempty"`
+	KeyType            string    `bson:"key_type,omitempty" json:"key_type,omitempty"` // RSA, ECDSA, Ed25519
+	KeySize            int       `bson:"key_size,omitempty" json:"key_size,omitempty"` // bits
+	SignatureAlgorithm string    `bson:"signature_algorithm,omitempty" json:"signature_algorithm,omitempty"`
+	Fingerprint        string    `bson:"fingerprint,omitempty" json:"fingerprint,omitempty"`           // SHA-256 do certificado (hex)
+	SPKIFingerprint    string    `bson:"spki_fingerprint,omitempty" json:"spki_fingerprint,omitempty"` // SHA-256 da chave pública (hex)
+	PinMatched         *bool     `bson:"pin_matched,omitempty" json:"pin_matched,omitempty"`           // nil quando não há pins configurados
+
+	// Chain
+	ChainVerified bool              `bson:"chain_verified" json:"chain_verified"`
+	HostnameMatch bool              `bson:"hostname_match" json:"hostname_match"`
+	ChainComplete bool              `bson:"chain_complete" json:"chain_complete"`
+	Chain         []CertificateInfo `bson:"chain,omitempty" json:"chain,omitempty"` // intermediários enviados pelo servidor
+
+	// Connection
+	TLSVersion  string `bson:"tls_version,omitempty" json:"tls_version,omitempty"`
+	CipherSuite string `bson:"cipher_suite,omitempty" json:"cipher_suite,omitempty"`
+
+	Revocation *RevocationStatus `bson:"revocation,omitempty" json:"revocation,omitempty"`
+
+	Problems  []string  `bson:"problems,omitempty" json:"problems,omitempty"`
+	CheckedAt time.Time `bson:"checked_at,omitempty" json:"checked_at,omitempty"`
+
+	// Alert Control (mantidos entre verificações)
+	AlertedThreshold int `bson:"alerted_threshold,omitempty" json:"alerted_threshold,omitempty"` // último limite de dias notificado
+}
+
+// CertificateInfo - Resumo de um certificado da cadeia
+type CertificateInfo struct {
+	Subject  string    `bson:"subject" json:"subject"`
+	Issuer   string    `bson:"issuer" json:"issuer"`
+	NotAfter time.Time `bson:"not_after" json:"not_after"`
+	DaysLeft int       `bson:"days_left" json:"days_left"`
+}
+
+// RevocationStatus - Resultado da verificação de revogação do certificado (OCSP ou CRL)
+type RevocationStatus struct {
+	Status    RevocationState `bson:"status" json:"status"`
+	Source    string          `bson:"source,omitempty" json:"source,omitempty"` // ocsp-stapled, ocsp ou crl
+	RevokedAt time.Time       `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	Reason    string          `bson:"reason,omitempty" json:"reason,omitempty"`
+	Error     string          `bson:"error,omitempty" json:"error,omitempty"`
+	CheckedAt time.Time       `bson:"checked_at" json:"checked_at"`
+}
+
+// MaxSSLHistory é o número de certificados mantidos no histórico do endpoint
+const MaxSSLHistory = 20
+
+// CertificateRecord - Certificado servido pelo endpoint em algum momento (histórico de trocas)
+type CertificateRecord struct {
+	Fingerprint     string    `bson:"fingerprint" json:"fingerprint"`
+	SPKIFingerprint string    `bson:"spki_fingerprint" json:"spki_fingerprint"`
+	SerialNumber    string    `bson:"serial_number" json:"serial_number"`
+	Issuer          string    `bson:"issuer" json:"issuer"`
+	Subject         string    `bson:"subject" json:"subject"`
+	SANs            []string  `bson:"sans,omitempty" json:"sans,omitempty"`
+	NotBefore       time.Time `bson:"not_before" json:"not_before"`
+	NotAfter        time.Time `bson:"not_after" json:"not_after"`
+	FirstSeen       time.Time `bson:"first_seen" json:"first_seen"`
+	Changes         []string  `bson:"changes,omitempty" json:"changes,omitempty"` // diferenças em relação ao certificado anterior
+}
+
+// CertificateRecord devolve o registro de histórico do certificado do relatório
+func (d SSLData) CertificateRecord() CertificateRecord {
+	return CertificateRecord{
+		Fingerprint:     d.Fingerprint,
+		SPKIFingerprint: d.SPKIFingerprint,
+		SerialNumber:    d.SerialNumber,
+		Issuer:          d.Issuer,
+		Subject:         d.Subject,
+		SANs:            d.SANs,
+		NotBefore:       d.NotBefore,
+		NotAfter:        d.ExpirationDate,
+		FirstSeen:       d.CheckedAt,
+	}
+}
+
+// InventoryCertificate - Certificado distinto servido por um ou mais endpoints (inventário)
+type InventoryCertificate struct {
+	Fingerprint    string                `json:"fingerprint,omitempty"`
+	Subject        string                `json:"subject"`
+	SANs           []string              `json:"sans,omitempty"`
+	Issuer         string                `json:"issuer"`
+	SerialNumber   string                `json:"serial_number,omitempty"`
+	ExpirationDate time.Time             `json:"expiration_date"`
+	DaysLeft       int                   `json:"days_left"`
+	Status         SSLStatus             `json:"status,omitempty"`
+	Endpoints      []CertificateEndpoint `json:"endpoints"`
+}
+
+// CertificateEndpoint - Endpoint que serve um certificado do inventário
+type CertificateEndpoint struct {
+	ID     primitive.ObjectID `json:"id"`
+	Name   string             `json:"name"`
+	Domain string             `json:"domain"`
+}
+
+// CertificateInventory agrupa os certificados atuais dos endpoints pelo fingerprint (ou serial + emissor
+// para relatórios gravados antes do fingerprint existir). DaysLeft é recalculado em relação a now, já que o
+// último scan pode ter horas (ou, em endpoints desabilitados, meses).
+func CertificateInventory(endpoints []Endpoint, now time.Time) []InventoryCertificate {
+	index := make(map[string]int)
+	var inventory []InventoryCertificate
+	for _, e := range endpoints {
+		d := e.SSLData
+		if d.CheckedAt.IsZero() || d.ExpirationDate.IsZero() {
+			continue
+		}
+		key := d.Fingerprint
+		if key == "" {
+			key = d.Issuer + "|" + d.SerialNumber
+		}
+		ref := CertificateEndpoint{ID: e.ID, Name: e.Name, Domain: e.Domain}
+		if i, ok := index[key]; ok {
+			inventory[i].Endpoints = append(inventory[i].Endpoints, ref)
+			continue
+		}
+		index[key] = len(inventory)
+		inventory = append(inventory, InventoryCertificate{
+			Fingerprint:    d.Fingerprint,
+			Subject:        d.Subject,
+			SANs:           d.SANs,
+			Issuer:         d.Issuer,
+			SerialNumber:   d.SerialNumber,
+			ExpirationDate: d.ExpirationDate,
+			DaysLeft:       DaysUntil(d.ExpirationDate, now),
+			Status:         d.Status,
+			Endpoints:      []CertificateEndpoint{ref},
+		})
+	}
+	return inventory
+}
+
+// DaysUntil devolve os dias inteiros restantes até t (negativo depois de t)
+func DaysUntil(t, now time.Time) int {
+	return int(math.Floor(t.Sub(now).Hours() / 24))
+}
@@ -0,0 +1,28 @@
+package entities
+
+import "testing"
+
+func TestSSLCheckCrossedThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    *SSLCheck
+		daysLeft int
+		want     int
+	}{
+		{"sem limite atingido", nil, 45, 0},
+		{"exatamente no limite", nil, 30, 30},
+		{"entre dois limites", nil, 10, 14},
+		{"menor limite", nil, 1, 1},
+		{"expirado", nil, -3, 1},
+		{"limites configurados fora de ordem", &SSLCheck{AlertDays: []int{3, 45, 10}}, 8, 10},
+		{"limites configurados sem limite atingido", &SSLCheck{AlertDays: []int{5}}, 6, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.CrossedThreshold(tt.daysLeft); got != tt.want {
+				t.Errorf("CrossedThreshold(%d) = %d, want %d", tt.daysLeft, got, tt.want)
+			}
+		})
+	}
+}
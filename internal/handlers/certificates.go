@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateHandler struct {
+	repo repositories.EndpointRepository
+}
+
+func NewCertificateHandler(repo repositories.EndpointRepository) *CertificateHandler {
+	return &CertificateHandler{repo: repo}
+}
+
+// ListCertificates lista os certificados distintos servidos pelos endpoints.
+//
+//	?sort=expiry|-expiry     ordem pela data de expiração (padrão: expiry, os que vencem antes primeiro)
+//	?issuer=                 filtra pelo emissor (contém, sem diferenciar maiúsculas)
+//	?max_days= / ?min_days=  filtra pelos dias restantes
+//	?format=csv              exporta como CSV
+func (h *CertificateHandler) ListCertificates(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort", "expiry")
+	if sortBy != "expiry" && sortBy != "-expiry" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort deve ser expiry ou -expiry"})
+		return
+	}
+	minDays, okMin := queryInt(c, "min_days")
+	maxDays, okMax := queryInt(c, "max_days")
+	if !okMin || !okMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_days e max_days devem ser números inteiros"})
+		return
+	}
+	issuer := strings.ToLower(c.Query("issuer"))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	endpoints, err := h.repo.FindAll(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	certificates := []entities.InventoryCertificate{}
+	for _, cert := range entities.CertificateInventory(endpoints, time.Now()) {
+		if issuer != "" && !strings.Contains(strings.ToLower(cert.Issuer), issuer) {
+			continue
+		}
+		if minDays != nil && cert.DaysLeft < *minDays {
+			continue
+		}
+		if maxDays != nil && cert.DaysLeft > *maxDays {
+			continue
+		}
+		certificates = append(certificates, cert)
+	}
+	sort.SliceStable(certificates, func(i, j int) bool {
+		if sortBy == "-expiry" {
+			return certificates[i].ExpirationDate.After(certificates[j].ExpirationDate)
+		}
+		return certificates[i].ExpirationDate.Before(certificates[j].ExpirationDate)
+	})
+
+	if c.Query("format") == "csv" {
+		writeCertificatesCSV(c, certificates)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"total":        len(certificates),
+		"certificates": certificates,
+	})
+}
+
+func writeCertificatesCSV(c *gin.Context, certificates []entities.InventoryCertificate) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="certificates.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"subject", "sans", "issuer", "serial_number", "fingerprint", "expiration_date", "days_left", "status", "endpoints"})
+	for _, cert := range certificates {
+		names := make([]string, 0, len(cert.Endpoints))
+		for _, e := range cert.Endpoints {
+			names = append(names, e.Name)
+		}
+		_ = w.Write([]string{
+			csvCell(cert.Subject),
+			csvCell(strings.Join(cert.SANs, " ")),
+			csvCell(cert.Issuer),
+			cert.SerialNumber,
+			cert.Fingerprint,
+			cert.ExpirationDate.UTC().Format(time.RFC3339),
+			strconv.Itoa(cert.DaysLeft),
+			string(cert.Status),
+			csvCell(strings.Join(names, "; ")),
+		})
+	}
+	w.Flush()
+}
+
+// csvCell prefixa com ' as células que uma planilha interpretaria como fórmula. Subject, emissor e SANs
+// vêm do certificado remoto e os nomes dos endpoints, dos usuários.
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// queryInt lê um parâmetro inteiro opcional; ok é false quando o valor informado é inválido
+func queryInt(c *gin.Context, name string) (*int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, false
+	}
+	return &v, true
+}
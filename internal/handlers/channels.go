@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AlertChannelHandler struct {
+	repo repositories.AlertChannelRepository
+}
+
+func NewAlertChannelHandler(repo repositories.AlertChannelRepository) *AlertChannelHandler {
+	return &AlertChannelHandler{repo: repo}
+}
+
+// ListChannels lista os canais de alerta
+func (h *AlertChannelHandler) ListChannels(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	channels, err := h.repo.FindAll(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":    len(channels),
+		"channels": channels,
+	})
+}
+
+// CreateChannel cria um canal de alerta (slack, telegram, ...)
+func (h *AlertChannelHandler) CreateChannel(c *gin.Context) {
+	var ch entities.AlertChannel
+	if err := c.ShouldBindJSON(&ch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if err := validateChannel(&ch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	ch.ID = primitive.NilObjectID
+	id, err := h.repo.Create(ctx, &ch)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ch.ID = id
+	c.JSON(http.StatusCreated, gin.H{
+		"channel": ch,
+	})
+}
+
+// UpdateChannel substitui a configuração de um canal de alerta
+func (h *AlertChannelHandler) UpdateChannel(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var ch entities.AlertChannel
+	if err := c.ShouldBindJSON(&ch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if err := validateChannel(&ch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.repo.FindByIDs(ctx, []primitive.ObjectID{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(current) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Canal não encontrado"})
+		return
+	}
+	ch.KeepSecrets(&current[0])
+
+	ch.ID = id
+	if err := h.repo.Update(ctx, &ch); err != nil {
+		respondRepoError(c, err, "Canal não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"channel": ch,
+	})
+}
+
+// DeleteChannel remove um canal de alerta
+func (h *AlertChannelHandler) DeleteChannel(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.repo.Delete(ctx, id); err != nil {
+		respondRepoError(c, err, "Canal não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Canal removido com sucesso",
+		"id":      id,
+	})
+}
+
+type AlertGroupHandler struct {
+	repo repositories.AlertGroupRepository
+}
+
+func NewAlertGroupHandler(repo repositories.AlertGroupRepository) *AlertGroupHandler {
+	return &AlertGroupHandler{repo: repo}
+}
+
+// ListGroups lista os grupos de alerta
+func (h *AlertGroupHandler) ListGroups(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	groups, err := h.repo.FindAll(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":  len(groups),
+		"groups": groups,
+	})
+}
+
+// CreateGroup cria um grupo de canais de alerta
+func (h *AlertGroupHandler) CreateGroup(c *gin.Context) {
+	var g entities.AlertGroup
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if g.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name é obrigatório"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	g.ID = primitive.NilObjectID
+	id, err := h.repo.Create(ctx, &g)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	g.ID = id
+	c.JSON(http.StatusCreated, gin.H{
+		"group": g,
+	})
+}
+
+// UpdateGroup substitui o nome e os canais de um grupo
+func (h *AlertGroupHandler) UpdateGroup(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var g entities.AlertGroup
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if g.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name é obrigatório"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	g.ID = id
+	if err := h.repo.Update(ctx, &g); err != nil {
+		respondRepoError(c, err, "Grupo não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group": g,
+	})
+}
+
+// DeleteGroup remove um grupo de alerta
+func (h *AlertGroupHandler) DeleteGroup(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.repo.Delete(ctx, id); err != nil {
+		respondRepoError(c, err, "Grupo não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Grupo removido com sucesso",
+		"id":      id,
+	})
+}
+
+// validateChannel verifica o tipo, o horário silencioso e o rate limit do canal
+func validateChannel(ch *entities.AlertChannel) error {
+	if ch.Name == "" {
+		return errors.New("Name é obrigatório")
+	}
+	switch ch.Type {
+	case "slack", "telegram", "email":
+	default:
+		return errors.New("type deve ser slack, telegram ou email")
+	}
+	if ch.QuietHours != nil {
+		if err := ch.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+	if ch.RateLimit != nil && (ch.RateLimit.PerMinute < 0 || ch.RateLimit.PerHour < 0) {
+		return errors.New("rate_limit não pode ser negativo")
+	}
+	return nil
+}
+
+// respondRepoError traduz mongo.ErrNoDocuments em 404 e os demais erros em 500
+func respondRepoError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
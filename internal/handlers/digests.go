@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DigestHandler struct {
+	repo repositories.DigestRepository
+}
+
+func NewDigestHandler(repo repositories.DigestRepository) *DigestHandler {
+	return &DigestHandler{repo: repo}
+}
+
+// ListDigests lista os digests agendados
+func (h *DigestHandler) ListDigests(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	digests, err := h.repo.FindAll(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":   len(digests),
+		"digests": digests,
+	})
+}
+
+// CreateDigest agenda um novo digest diário ou semanal
+func (h *DigestHandler) CreateDigest(c *gin.Context) {
+	var d entities.Digest
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if err := d.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	d.ID = primitive.NilObjectID
+	d.LastSentAt = time.Time{}
+	id, err := h.repo.Create(ctx, &d)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	d.ID = id
+	c.JSON(http.StatusCreated, gin.H{
+		"digest": d,
+	})
+}
+
+// UpdateDigest altera o agendamento, o escopo e os canais de um digest
+func (h *DigestHandler) UpdateDigest(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var d entities.Digest
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if err := d.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	d.ID = id
+	if err := h.repo.Update(ctx, &d); err != nil {
+		respondRepoError(c, err, "Digest não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Digest atualizado com sucesso",
+		"id":      id,
+	})
+}
+
+// DeleteDigest remove um digest
+func (h *DigestHandler) DeleteDigest(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.repo.Delete(ctx, id); err != nil {
+		respondRepoError(c, err, "Digest não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Digest removido com sucesso",
+		"id":      id,
+	})
+}
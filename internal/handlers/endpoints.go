@@ -2,20 +2,25 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/monitors"
 	"github.com/brunohfonseca/ratatoskr/internal/repositories"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type EndpointHandler struct {
-	repo repositories.EndpointRepository
+	repo    repositories.EndpointRepository
+	history repositories.HistoryRepository
 }
 
-func NewEndpointHandler(repo repositories.EndpointRepository) *EndpointHandler {
-	return &EndpointHandler{repo: repo}
+func NewEndpointHandler(repo repositories.EndpointRepository, history repositories.HistoryRepository) *EndpointHandler {
+	return &EndpointHandler{repo: repo, history: history}
 }
 
 // CreateService cria um novo endpoint
@@ -26,11 +31,19 @@ func (h *EndpointHandler) CreateService(c *gin.Context) {
 		return
 	}
 
-	// valida campos obrigatórios
-	if e.Name == "" || e.Domain == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Name e Domain são obrigatórios"})
+	if err := validateEndpoint(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if e.MonitorType() == entities.TypeHeartbeat {
+		token, err := monitors.NewHeartbeatToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		e.Heartbeat.Token = token
+		e.Pings = nil
+	}
 
 	// contexto com timeout baseado no request
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
@@ -66,18 +79,82 @@ func (h *EndpointHandler) ListServices(c *gin.Context) {
 }
 
 // GetService busca um endpoint específico por ID
-func GetService(c *gin.Context) {
-	id := c.Param("id")
+func (h *EndpointHandler) GetService(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	e, err := h.repo.FindByID(ctx, id)
+	if err != nil {
+		respondRepoError(c, err, "Endpoint não encontrado")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"endpoint": id,
+		"endpoint": e,
 	})
 }
 
-// UpdateService atualiza um endpoint existente
-func UpdateService(c *gin.Context) {
-	id := c.Param("id")
+// UpdateService atualiza a configuração de um endpoint existente, mantendo status e histórico.
+// Segredos enviados mascarados ("********") mantêm o valor salvo.
+func (h *EndpointHandler) UpdateService(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var e entities.Endpoint
+	if err := c.ShouldBindJSON(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.repo.FindByID(ctx, id)
+	if err != nil {
+		respondRepoError(c, err, "Endpoint não encontrado")
+		return
+	}
+	if e.Authentication != nil {
+		e.Authentication.KeepSecrets(current.Authentication)
+	}
+	if e.GRPC != nil {
+		e.GRPC.KeepSecrets(current.GRPC)
+	}
+	if e.WebSocket != nil {
+		e.WebSocket.KeepSecrets(current.WebSocket)
+	}
+
+	if err := validateEndpoint(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if e.MonitorType() == entities.TypeHeartbeat {
+		// o token só é gerado na criação (ou quando o endpoint passa a ser heartbeat)
+		if current.Heartbeat != nil && current.Heartbeat.Token != "" {
+			e.Heartbeat.Token = current.Heartbeat.Token
+		} else if e.Heartbeat.Token, err = monitors.NewHeartbeatToken(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	e.ID = id
+	if err := h.repo.Update(ctx, &e); err != nil {
+		respondRepoError(c, err, "Endpoint não encontrado")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"endpoint": id,
+		"endpoint": e,
 		"message":  "Endpoint atualizado com sucesso",
 	})
 }
@@ -92,18 +169,39 @@ func DeleteService(c *gin.Context) {
 	})
 }
 
-// GetServiceStatus retorna o status atual do serviço
-func GetServiceStatus(c *gin.Context) {
-	id := c.Param("id")
+// GetServiceStatus retorna o status atual do serviço e as fases do último check
+func (h *EndpointHandler) GetServiceStatus(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
 
-	// TODO: Implementar verificação real de status
-	c.JSON(http.StatusOK, gin.H{
-		"service_id":    id,
-		"status":        "online",
-		"last_check":    "2024-01-01T00:00:00Z",
-		"response_time": 120,
-		"message":       "Status do serviço (implementação pendente)",
-	})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	e, err := h.repo.FindByID(ctx, id)
+	if err != nil {
+		respondRepoError(c, err, "Endpoint não encontrado")
+		return
+	}
+	last, err := h.history.FindByEndpoint(ctx, id, 1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := gin.H{
+		"service_id":       e.ID,
+		"status":           e.Status,
+		"last_check":       e.LastCheck,
+		"response_time_ms": e.ResponseTime,
+		"error_message":    e.ErrorMessage,
+	}
+	if len(last) > 0 && last[0].Timings != nil {
+		status["timings"] = last[0].Timings
+	}
+	c.JSON(http.StatusOK, status)
 }
 
 // TriggerHealthCheck força uma verificação de health check
@@ -118,16 +216,68 @@ func TriggerHealthCheck(c *gin.Context) {
 	})
 }
 
-// GetServiceHistory retorna o histórico de health checks
-func GetServiceHistory(c *gin.Context) {
-	id := c.Param("id")
+// DryRunScript executa o script de um monitor starlark sem salvá-lo e devolve o resultado e os logs (print).
+// O script roda no processo da API, a partir da rede da API e não da dos workers: qualquer usuário
+// autenticado consegue fazer requests http/dns/tcp para hosts alcançáveis pela API e ler as respostas.
+// Restrinja a saída de rede da API se ela tiver acesso a serviços internos que os workers não têm.
+func (h *EndpointHandler) DryRunScript(c *gin.Context) {
+	var e entities.Endpoint
+	if err := c.ShouldBindJSON(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if e.Type == "" {
+		e.Type = entities.TypeStarlark
+	}
+	if e.MonitorType() != entities.TypeStarlark {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry-run disponível apenas para monitores starlark"})
+		return
+	}
+	if err := monitors.ValidateEndpoint(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, logs := monitors.DryRunStarlark(c.Request.Context(), &e)
+	if logs == nil {
+		logs = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":        result.Status,
+		"message":       result.ErrorMessage,
+		"metrics":       result.Metrics,
+		"response_time": result.ResponseTime.Milliseconds(),
+		"logs":          logs,
+	})
+}
+
+// GetServiceHistory retorna os health checks mais recentes (?limit=, padrão 50, máximo 500)
+func (h *EndpointHandler) GetServiceHistory(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 64)
+	if err != nil || limit <= 0 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve estar entre 1 e 500"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	history, err := h.history.FindByEndpoint(ctx, id, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	// TODO: Buscar histórico no MongoDB
 	c.JSON(http.StatusOK, gin.H{
 		"service_id": id,
-		"history":    []gin.H{},
-		"total":      0,
-		"message":    "Histórico do serviço (implementação pendente)",
+		"history":    history,
+		"total":      len(history),
 	})
 }
 
@@ -147,3 +297,17 @@ func GetServiceUptime(c *gin.Context) {
 		"message": "Estatísticas de uptime (implementação pendente)",
 	})
 }
+
+// domainOptional indica os tipos que não exigem Domain: o alvo vem do próprio check ou, no heartbeat, não existe
+func domainOptional(t entities.EndpointType) bool {
+	return t == entities.TypeHeartbeat || t == entities.TypeExec || t == entities.TypeStarlark
+}
+
+// validateEndpoint verifica os campos obrigatórios e a configuração do tipo de monitor
+func validateEndpoint(e *entities.Endpoint) error {
+	// heartbeat não tem Domain (o job é quem chama a API); exec e starlark definem o alvo no próprio check
+	if e.Name == "" || (e.Domain == "" && !domainOptional(e.MonitorType())) {
+		return errors.New("Name e Domain são obrigatórios")
+	}
+	return monitors.ValidateEndpoint(e)
+}
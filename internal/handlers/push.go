@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPingMessage limita o texto (ex.: saída do job) guardado junto com o ping
+const maxPingMessage = 1000
+
+type PushHandler struct {
+	repo repositories.EndpointRepository
+}
+
+func NewPushHandler(repo repositories.EndpointRepository) *PushHandler {
+	return &PushHandler{repo: repo}
+}
+
+type pingPayload struct {
+	ExitCode *int   `json:"exit_code" form:"exit_code"`
+	Message  string `json:"message" form:"message"`
+}
+
+// Ping registra que o job terminou; exit_code diferente de zero é tratado como falha
+func (h *PushHandler) Ping(c *gin.Context) {
+	h.record(c, "success")
+}
+
+// Start registra o início da execução do job
+func (h *PushHandler) Start(c *gin.Context) {
+	h.record(c, "start")
+}
+
+// Fail registra que o job terminou com erro
+func (h *PushHandler) Fail(c *gin.Context) {
+	h.record(c, "fail")
+}
+
+func (h *PushHandler) record(c *gin.Context, kind string) {
+	// o payload é opcional: aceita query string, form ou JSON
+	var payload pingPayload
+	if c.Request.ContentLength != 0 || c.Request.URL.RawQuery != "" {
+		if err := c.ShouldBind(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido: " + err.Error()})
+			return
+		}
+	}
+
+	exitCode := 0
+	if payload.ExitCode != nil {
+		exitCode = *payload.ExitCode
+	}
+	if kind == "fail" && exitCode == 0 {
+		exitCode = 1
+	}
+	if kind == "success" && exitCode != 0 {
+		kind = "fail"
+	}
+	if len(payload.Message) > maxPingMessage {
+		payload.Message = payload.Message[:maxPingMessage]
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.repo.RecordPing(ctx, c.Param("token"), kind, exitCode, payload.Message, time.Now()); err != nil {
+		respondRepoError(c, err, "Heartbeat não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    kind,
+		"exit_code": exitCode,
+	})
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/notifications"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SlackHandler struct {
+	cfg       *config.AppConfig
+	incidents repositories.IncidentRepository
+	endpoints repositories.EndpointRepository
+}
+
+func NewSlackHandler(cfg *config.AppConfig, incidents repositories.IncidentRepository, endpoints repositories.EndpointRepository) *SlackHandler {
+	return &SlackHandler{cfg: cfg, incidents: incidents, endpoints: endpoints}
+}
+
+// HandleInteraction recebe os cliques nos botões das mensagens de incidente enviadas ao Slack
+func (h *SlackHandler) HandleInteraction(c *gin.Context) {
+	if h.cfg.Alerts.Slack.SigningSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Integração com o Slack não configurada"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Corpo da requisição inválido"})
+		return
+	}
+
+	// valida a assinatura enviada pelo Slack (X-Slack-Signature / X-Slack-Request-Timestamp)
+	verifier, err := slack.NewSecretsVerifier(c.Request.Header, h.cfg.Alerts.Slack.SigningSecret)
+	if err != nil {
+		log.Warn().Err(err).Msg("Cabeçalhos de assinatura do Slack inválidos")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Assinatura inválida"})
+		return
+	}
+	if _, err := verifier.Write(body); err != nil {
+		log.Error().Err(err).Msg("Erro ao verificar assinatura do Slack")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar assinatura"})
+		return
+	}
+	if err := verifier.Ensure(); err != nil {
+		log.Warn().Err(err).Msg("Assinatura do Slack inválida")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Assinatura inválida"})
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido"})
+		return
+	}
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form.Get("payload")), &callback); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido: " + err.Error()})
+		return
+	}
+	if callback.Type != slack.InteractionTypeBlockActions {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	user := callback.User.Name
+	if user == "" {
+		user = callback.User.ID
+	}
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		incident, endpoint, err := h.applyAction(ctx, action, user)
+		if err != nil {
+			log.Error().Err(err).Str("action", action.ActionID).Msg("Erro ao processar ação do Slack")
+			c.JSON(http.StatusOK, gin.H{"response_type": "ephemeral", "text": "Não foi possível atualizar o incidente: " + err.Error()})
+			return
+		}
+		if incident == nil || callback.ResponseURL == "" {
+			continue
+		}
+
+		// atualiza a mensagem original com o novo estado do incidente
+		blocks := notifications.BuildIncidentBlocks(h.cfg, endpoint, incident, callback.Message.Text)
+		msg := &slack.WebhookMessage{
+			Text:            callback.Message.Text,
+			ReplaceOriginal: true,
+			Blocks:          &slack.Blocks{BlockSet: blocks},
+		}
+		if err := slack.PostWebhookContext(ctx, callback.ResponseURL, msg); err != nil {
+			log.Error().Err(err).Msg("Failed to update Slack message")
+		}
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// applyAction executa a ação do botão e devolve o incidente e o endpoint atualizados
+func (h *SlackHandler) applyAction(ctx context.Context, action *slack.BlockAction, user string) (*entities.Incident, *entities.Endpoint, error) {
+	id, err := primitive.ObjectIDFromHex(action.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var incident *entities.Incident
+	switch action.ActionID {
+	case notifications.SlackActionAcknowledge:
+		incident, err = h.incidents.Acknowledge(ctx, id, user)
+	case notifications.SlackActionResolve:
+		incident, err = h.incidents.Resolve(ctx, id, user)
+	case notifications.SlackActionMute:
+		incident, err = h.incidents.FindByID(ctx, id)
+		if err == nil {
+			err = h.endpoints.Mute(ctx, incident.EndpointID, time.Now().Add(time.Hour))
+		}
+	default:
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Info().
+		Str("incident_id", id.Hex()).
+		Str("action", action.ActionID).
+		Str("user", user).
+		Msg("Ação de incidente recebida do Slack")
+
+	endpoint, err := h.endpoints.FindByID(ctx, incident.EndpointID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return incident, endpoint, nil
+}
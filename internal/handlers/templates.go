@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/notifications"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TemplateHandler struct {
+	repo repositories.TemplateRepository
+}
+
+func NewTemplateHandler(repo repositories.TemplateRepository) *TemplateHandler {
+	return &TemplateHandler{repo: repo}
+}
+
+// ListTemplates lista os templates de notificação cadastrados
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	templates, err := h.repo.FindAll(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":     len(templates),
+		"templates": templates,
+	})
+}
+
+// CreateTemplate cria um template para um canal ou grupo de alerta
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var t entities.NotificationTemplate
+	if err := c.ShouldBindJSON(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if err := validateTemplate(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	t.ID = primitive.NilObjectID
+	id, err := h.repo.Create(ctx, &t)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	t.ID = id
+	c.JSON(http.StatusCreated, gin.H{
+		"template": t,
+	})
+}
+
+// UpdateTemplate atualiza o tipo de evento e o corpo de um template
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var t entities.NotificationTemplate
+	if err := c.ShouldBindJSON(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if !t.EventType.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event_type inválido"})
+		return
+	}
+	if _, err := notifications.ParseTemplate(t.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template inválido: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	t.ID = id
+	if err := h.repo.Update(ctx, &t); err != nil {
+		respondRepoError(c, err, "Template não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template atualizado com sucesso",
+		"id":      id,
+	})
+}
+
+// DeleteTemplate remove um template
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.repo.Delete(ctx, id); err != nil {
+		respondRepoError(c, err, "Template não encontrado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template removido com sucesso",
+		"id":      id,
+	})
+}
+
+// PreviewTemplate renderiza um template com dados de exemplo, sem salvá-lo
+func (h *TemplateHandler) PreviewTemplate(c *gin.Context) {
+	var req struct {
+		EventType entities.EventType `json:"event_type"`
+		Body      string             `json:"body"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	if !req.EventType.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event_type inválido"})
+		return
+	}
+	if req.Body == "" {
+		req.Body = notifications.DefaultTemplates[req.EventType]
+	}
+
+	data := notifications.SampleTemplateData(req.EventType)
+	rendered, err := notifications.RenderTemplate(req.Body, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template inválido: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event_type": req.EventType,
+		"rendered":   rendered,
+		"data":       data,
+	})
+}
+
+// validateTemplate verifica o dono, o tipo de evento e a sintaxe do template
+func validateTemplate(t *entities.NotificationTemplate) error {
+	if (t.ChannelID == nil) == (t.GroupID == nil) {
+		return errors.New("informe channel_id ou group_id")
+	}
+	if !t.EventType.IsValid() {
+		return errors.New("event_type inválido")
+	}
+	if t.Body == "" {
+		return errors.New("body é obrigatório")
+	}
+	if _, err := notifications.ParseTemplate(t.Body); err != nil {
+		return errors.New("Template inválido: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package monitors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/tidwall/gjson"
+)
+
+// defaultOperators é o operador usado quando a assertion não informa um
+var defaultOperators = map[string]string{
+	"body":    "contains",
+	"json":    "eq",
+	"header":  "eq",
+	"size":    "lte",
+	"latency": "lt",
+}
+
+var validOperators = map[string]bool{
+	"eq": true, "ne": true, "contains": true, "not_contains": true, "matches": true,
+	"lt": true, "lte": true, "gt": true, "gte": true, "exists": true, "not_exists": true,
+}
+
+func validateAssertions(assertions []entities.Assertion) error {
+	for i, a := range assertions {
+		if _, ok := defaultOperators[a.Type]; !ok {
+			return fmt.Errorf("assertions[%d].type inválido: %s", i, a.Type)
+		}
+		op := operator(a)
+		if !validOperators[op] {
+			return fmt.Errorf("assertions[%d].operator inválido: %s", i, a.Operator)
+		}
+		if (a.Type == "json" || a.Type == "header") && a.Target == "" {
+			return fmt.Errorf("assertions[%d].target é obrigatório para %s", i, a.Type)
+		}
+		if op == "matches" {
+			if _, err := regexp.Compile(a.Value); err != nil {
+				return fmt.Errorf("assertions[%d].value não é uma regex válida: %w", i, err)
+			}
+		}
+		if a.Type == "size" || a.Type == "latency" {
+			if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+				return fmt.Errorf("assertions[%d].value deve ser numérico", i)
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateAssertions aplica as assertions à resposta; size é o tamanho total do body em bytes
+func evaluateAssertions(assertions []entities.Assertion, resp *http.Response, body []byte, size int64, elapsed time.Duration) []entities.AssertionResult {
+	results := make([]entities.AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		var actual string
+		exists := true
+
+		switch a.Type {
+		case "body":
+			actual = string(body)
+		case "json":
+			value := gjson.GetBytes(body, a.Target)
+			actual, exists = value.String(), value.Exists()
+		case "header":
+			values := resp.Header.Values(a.Target)
+			actual, exists = strings.Join(values, ", "), len(values) > 0
+		case "size":
+			actual = strconv.FormatInt(size, 10)
+		case "latency":
+			actual = strconv.FormatInt(elapsed.Milliseconds(), 10)
+		}
+
+		result := entities.AssertionResult{Assertion: a, Actual: truncateBanner(actual)}
+		if a.Type == "body" {
+			// o body inteiro não é útil no histórico
+			result.Actual = ""
+		}
+		result.Passed, result.Message = compare(a, actual, exists)
+		results = append(results, result)
+	}
+	return results
+}
+
+// assertionFailures devolve a descrição de cada assertion que falhou
+func assertionFailures(results []entities.AssertionResult) []string {
+	var failures []string
+	for _, r := range results {
+		if !r.Passed {
+			failures = append(failures, r.Message)
+		}
+	}
+	return failures
+}
+
+func compare(a entities.Assertion, actual string, exists bool) (bool, string) {
+	op := operator(a)
+	label := a.Type
+	if a.Target != "" {
+		label += " " + a.Target
+	}
+	fail := func() (bool, string) {
+		if a.Type == "body" {
+			return false, fmt.Sprintf("%s %s %q falhou", label, op, a.Value)
+		}
+		return false, fmt.Sprintf("%s %s %q falhou (atual: %q)", label, op, a.Value, truncateBanner(actual))
+	}
+
+	switch op {
+	case "exists":
+		if exists {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%s não existe", label)
+	case "not_exists":
+		if !exists {
+			return true, ""
+		}
+		return fail()
+	}
+
+	if !exists {
+		return false, fmt.Sprintf("%s não existe", label)
+	}
+
+	var passed bool
+	switch op {
+	case "eq":
+		passed = actual == a.Value
+	case "ne":
+		passed = actual != a.Value
+	case "contains":
+		passed = strings.Contains(actual, a.Value)
+	case "not_contains":
+		passed = !strings.Contains(actual, a.Value)
+	case "matches":
+		re, err := regexp.Compile(a.Value)
+		passed = err == nil && re.MatchString(actual)
+	case "lt", "lte", "gt", "gte":
+		cmp, err := compareNumbers(actual, a.Value)
+		if err != nil {
+			return false, fmt.Sprintf("%s: %v", label, err)
+		}
+		passed = (op == "lt" && cmp < 0) || (op == "lte" && cmp <= 0) ||
+			(op == "gt" && cmp > 0) || (op == "gte" && cmp >= 0)
+	}
+
+	if passed {
+		return true, ""
+	}
+	return fail()
+}
+
+func compareNumbers(actual, expected string) (int, error) {
+	x, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+	if err != nil {
+		return 0, errors.New("valor atual não é numérico: " + truncateBanner(actual))
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+	if err != nil {
+		return 0, errors.New("valor esperado não é numérico: " + expected)
+	}
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func operator(a entities.Assertion) string {
+	if a.Operator != "" {
+		return a.Operator
+	}
+	return defaultOperators[a.Type]
+}
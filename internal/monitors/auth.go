@@ -0,0 +1,195 @@
+package monitors
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// tokenTimeout limita a requisição de token oauth2, que acontece fora do contexto do check
+	tokenTimeout = 10 * time.Second
+	// authCacheTTL é o tempo sem uso após o qual um TokenSource ou client mtls sai do cache: credenciais
+	// trocadas e endpoints removidos deixam de ser usados e não ficam presos até o worker reiniciar
+	authCacheTTL = time.Hour
+)
+
+var (
+	// tokenSources guarda um TokenSource por configuração oauth2; ele reaproveita o token até expirar
+	tokenSources authCache[oauth2.TokenSource]
+	// mtlsClients guarda um client por certificado para não reler o par de chaves a cada check
+	mtlsClients = authCache[*http.Client]{evict: func(c *http.Client) { c.CloseIdleConnections() }}
+)
+
+// authCache guarda valores por fingerprint da credencial e descarta os que não são usados há authCacheTTL
+type authCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*cachedAuth[T]
+	swept   time.Time
+	evict   func(T) // opcional, chamado ao descartar uma entrada
+}
+
+type cachedAuth[T any] struct {
+	value    T
+	lastUsed time.Time
+}
+
+// get devolve o valor de key, criando-o com build quando não está no cache
+func (c *authCache[T]) get(key string, build func() (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.swept) >= time.Minute {
+		c.sweep(now)
+	}
+	if entry, ok := c.entries[key]; ok {
+		entry.lastUsed = now
+		return entry.value, nil
+	}
+
+	value, err := build()
+	if err != nil {
+		return value, err
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]*cachedAuth[T])
+	}
+	c.entries[key] = &cachedAuth[T]{value: value, lastUsed: now}
+	return value, nil
+}
+
+func (c *authCache[T]) sweep(now time.Time) {
+	c.swept = now
+	for key, entry := range c.entries {
+		if now.Sub(entry.lastUsed) < authCacheTTL {
+			continue
+		}
+		delete(c.entries, key)
+		if c.evict != nil {
+			c.evict(entry.value)
+		}
+	}
+}
+
+func validateAuth(a *entities.EndpointAuth) error {
+	if a == nil {
+		return nil
+	}
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if a.Type == entities.AuthMTLS {
+		if _, err := mtlsConfig(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkClient é o client dos checks http. Sem keep-alive cada check abre uma conexão nova, então dns,
+// connect e tls são sempre medidos em vez de sair zerados por uma conexão reaproveitada do pool
+var checkClient = &http.Client{Transport: checkTransport()}
+
+func checkTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+	return transport
+}
+
+// httpClient devolve o client do endpoint: checkClient ou um client com certificado (mtls)
+func httpClient(a *entities.EndpointAuth) (*http.Client, error) {
+	if a == nil || a.Type != entities.AuthMTLS {
+		return checkClient, nil
+	}
+
+	key := fingerprint(a.ClientCert, a.ClientKey, a.CACert)
+	return mtlsClients.get(key, func() (*http.Client, error) {
+		tlsConfig, err := mtlsConfig(a)
+		if err != nil {
+			return nil, err
+		}
+		transport := checkTransport()
+		transport.TLSClientConfig = tlsConfig
+		return &http.Client{Transport: transport}, nil
+	})
+}
+
+// applyAuth adiciona as credenciais ao request
+func applyAuth(req *http.Request, a *entities.EndpointAuth) error {
+	if a == nil {
+		return nil
+	}
+
+	switch a.Type {
+	case entities.AuthBasic:
+		req.SetBasicAuth(a.Username, a.Password)
+	case entities.AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	case entities.AuthAPIKey:
+		if a.In == "query" {
+			q := req.URL.Query()
+			q.Set(a.Key, a.Value)
+			req.URL.RawQuery = q.Encode()
+		} else {
+			req.Header.Set(a.Key, a.Value)
+		}
+	case entities.AuthOAuth2:
+		token, err := oauth2TokenSource(a).Token()
+		if err != nil {
+			return fmt.Errorf("erro ao obter token oauth2: %w", err)
+		}
+		token.SetAuthHeader(req)
+	}
+	return nil
+}
+
+func oauth2TokenSource(a *entities.EndpointAuth) oauth2.TokenSource {
+	key := fingerprint(a.TokenURL, a.ClientID, a.ClientSecret, strings.Join(a.Scopes, " "))
+	ts, _ := tokenSources.get(key, func() (oauth2.TokenSource, error) {
+		cfg := clientcredentials.Config{
+			ClientID:     a.ClientID,
+			ClientSecret: a.ClientSecret,
+			TokenURL:     a.TokenURL,
+			Scopes:       a.Scopes,
+		}
+		// o TokenSource sobrevive ao check, então não pode usar o contexto dele
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: tokenTimeout})
+		return cfg.TokenSource(ctx), nil
+	})
+	return ts
+}
+
+func mtlsConfig(a *entities.EndpointAuth) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair([]byte(a.ClientCert), []byte(a.ClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("certificado mtls inválido: %w", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if a.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(a.CACert)) {
+			return nil, errors.New("authentication.ca_cert inválido")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// fingerprint identifica uma configuração sem manter os segredos em texto nas chaves do cache
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,38 @@
+package monitors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthCacheSweep(t *testing.T) {
+	var evicted []string
+	cache := authCache[string]{evict: func(v string) { evicted = append(evicted, v) }}
+	build := func(v string) func() (string, error) {
+		return func() (string, error) { return v, nil }
+	}
+
+	if v, _ := cache.get("antiga", build("a")); v != "a" {
+		t.Fatalf("get() = %q, want a", v)
+	}
+	if v, _ := cache.get("antiga", build("outro")); v != "a" {
+		t.Fatalf("get() recriou a entrada: %q", v)
+	}
+
+	// simula a credencial antiga sem uso desde antes do TTL
+	cache.entries["antiga"].lastUsed = time.Now().Add(-authCacheTTL)
+	cache.swept = time.Time{}
+	if _, err := cache.get("nova", build("n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.entries["antiga"]; ok {
+		t.Error("entrada sem uso não foi descartada")
+	}
+	if _, ok := cache.entries["nova"]; !ok {
+		t.Error("entrada nova não foi guardada")
+	}
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Errorf("evict chamado com %q, want [a]", evicted)
+	}
+}
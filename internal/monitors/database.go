@@ -0,0 +1,397 @@
+package monitors
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// databasePorts são as portas padrão de cada tipo de monitor de banco
+var databasePorts = map[entities.EndpointType]int{
+	entities.TypePostgres: 5432,
+	entities.TypeMySQL:    3306,
+	entities.TypeMongoDB:  27017,
+	entities.TypeRedis:    6379,
+}
+
+// defaultQueries são as consultas executadas quando database.query não é informado
+var defaultQueries = map[entities.EndpointType]string{
+	entities.TypePostgres: "SELECT 1",
+	entities.TypeMySQL:    "SELECT 1",
+	entities.TypeMongoDB:  `{"ping": 1}`,
+	entities.TypeRedis:    "PING",
+}
+
+// redisReadCommands são os comandos aceitos em monitores redis; escritas (FLUSHALL, DEL, SET, ...) rodariam
+// a cada intervalo com as credenciais salvas
+var redisReadCommands = map[string]bool{
+	"PING": true, "ECHO": true, "TIME": true, "INFO": true, "DBSIZE": true, "LASTSAVE": true,
+	"GET": true, "MGET": true, "STRLEN": true, "EXISTS": true, "TYPE": true, "TTL": true, "PTTL": true,
+	"HGET": true, "HMGET": true, "HLEN": true, "HEXISTS": true,
+	"LLEN": true, "LINDEX": true, "SCARD": true, "SISMEMBER": true,
+	"ZCARD": true, "ZSCORE": true, "ZCOUNT": true, "XLEN": true,
+}
+
+// mongoReadCommands são os comandos aceitos em monitores mongodb (aggregate fica de fora por causa de $out/$merge)
+var mongoReadCommands = map[string]bool{
+	"ping": true, "hello": true, "ismaster": true, "buildinfo": true, "serverstatus": true,
+	"dbstats": true, "collstats": true, "count": true, "distinct": true, "find": true,
+	"listcollections": true, "listdatabases": true, "replsetgetstatus": true,
+}
+
+// validateReadOnly rejeita comandos de escrita no redis e no mongodb; no SQL a transação READ ONLY cuida disso
+func validateReadOnly(t entities.EndpointType, query string) error {
+	switch t {
+	case entities.TypeRedis:
+		fields := strings.Fields(query)
+		if len(fields) == 0 || !redisReadCommands[strings.ToUpper(fields[0])] {
+			return errors.New("database.query deve ser um comando de leitura do redis, ex.: PING, GET, LLEN")
+		}
+	case entities.TypeMongoDB:
+		var cmd bson.D
+		if err := bson.UnmarshalExtJSON([]byte(query), false, &cmd); err != nil || len(cmd) == 0 {
+			return errors.New("database.query deve ser um comando JSON para mongodb, ex.: {\"ping\": 1}")
+		}
+		if !mongoReadCommands[strings.ToLower(cmd[0].Key)] {
+			return fmt.Errorf("database.query: comando mongodb não permitido: %s", cmd[0].Key)
+		}
+	}
+	return nil
+}
+
+func validateDatabase(e *entities.Endpoint) error {
+	if e.Port < 0 || e.Port > 65535 {
+		return errors.New("port inválido")
+	}
+	if e.Authentication != nil {
+		if e.Authentication.Type != entities.AuthBasic {
+			return fmt.Errorf("monitores %s aceitam apenas authentication do tipo basic", e.MonitorType())
+		}
+		if err := validateAuth(e.Authentication); err != nil {
+			return err
+		}
+	}
+
+	cfg := databaseConfig(e)
+	if err := validateReadOnly(e.MonitorType(), cfg.Query); err != nil {
+		return err
+	}
+	if e.MonitorType() == entities.TypeRedis && cfg.Database != "" {
+		if _, err := strconv.Atoi(cfg.Database); err != nil {
+			return errors.New("database.database deve ser o número do DB no redis")
+		}
+	}
+
+	if cfg.Expect != "" || cfg.Operator != "" {
+		op := databaseAssertion(cfg).Operator
+		if !validOperators[op] {
+			return fmt.Errorf("database.operator inválido: %s", op)
+		}
+		if op == "matches" {
+			if _, err := regexp.Compile(cfg.Expect); err != nil {
+				return fmt.Errorf("database.expect não é uma regex válida: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// databaseConfig devolve a configuração do endpoint com a consulta padrão do tipo preenchida
+func databaseConfig(e *entities.Endpoint) entities.DatabaseCheck {
+	var cfg entities.DatabaseCheck
+	if e.Database != nil {
+		cfg = *e.Database
+	}
+	if strings.TrimSpace(cfg.Query) == "" {
+		cfg.Query = defaultQueries[e.MonitorType()]
+	}
+	return cfg
+}
+
+func databaseAddr(e *entities.Endpoint) string {
+	port := e.Port
+	if port == 0 {
+		port = databasePorts[e.MonitorType()]
+	}
+	return net.JoinHostPort(hostOnly(e.Domain), strconv.Itoa(port))
+}
+
+func databaseAssertion(cfg entities.DatabaseCheck) entities.Assertion {
+	op := cfg.Operator
+	if op == "" {
+		op = "eq"
+	}
+	return entities.Assertion{Type: "result", Operator: op, Value: cfg.Expect}
+}
+
+func databaseCredentials(e *entities.Endpoint) (string, string) {
+	if e.Authentication == nil {
+		return "", ""
+	}
+	return e.Authentication.Username, e.Authentication.Password
+}
+
+// checkSQL conecta no postgres ou mysql, mede a conexão (com autenticação) e a consulta separadamente
+func checkSQL(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := databaseConfig(e)
+
+	driver, dsn := sqlDSN(e, cfg)
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		result.ResponseTime = time.Since(start)
+		result.ErrorMessage = fmt.Sprintf("erro ao conectar: %v", err)
+		return result
+	}
+	connectTime := time.Since(start)
+	result.Metrics["connect_ms"] = milliseconds(connectTime)
+
+	queryStart := time.Now()
+	value, exists, err := sqlScalar(ctx, db, cfg.Query)
+	queryTime := time.Since(queryStart)
+	result.Metrics["query_ms"] = milliseconds(queryTime)
+	result.ResponseTime = connectTime + queryTime
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("erro na consulta: %v", err)
+		return result
+	}
+	return finishDatabaseCheck(result, cfg, value, exists)
+}
+
+func sqlDSN(e *entities.Endpoint, cfg entities.DatabaseCheck) (string, string) {
+	user, password := databaseCredentials(e)
+	timeout := Timeout(e)
+
+	if e.MonitorType() == entities.TypeMySQL {
+		c := mysql.NewConfig()
+		c.User = user
+		c.Passwd = password
+		c.Net = "tcp"
+		c.Addr = databaseAddr(e)
+		c.DBName = cfg.Database
+		c.Timeout = timeout
+		if cfg.TLS {
+			c.TLS = &tls.Config{ServerName: hostOnly(e.Domain), MinVersion: tls.VersionTLS12}
+		}
+		return "mysql", c.FormatDSN()
+	}
+
+	u := url.URL{Scheme: "postgres", Host: databaseAddr(e), Path: "/" + cfg.Database}
+	if user != "" {
+		u.User = url.UserPassword(user, password)
+	}
+	q := url.Values{}
+	q.Set("sslmode", "disable")
+	if cfg.TLS {
+		// valida o certificado e o hostname, como nos demais bancos
+		q.Set("sslmode", "verify-full")
+	}
+	q.Set("connect_timeout", strconv.Itoa(int(timeout.Seconds())))
+	u.RawQuery = q.Encode()
+	return "postgres", u.String()
+}
+
+// sqlScalar executa a consulta em uma transação READ ONLY (desfeita no fim) e devolve a primeira coluna da
+// primeira linha; exists é false sem linhas. A consulta é preparada para que o servidor recuse várias
+// instruções, o que permitiria sair da transação com um COMMIT.
+func sqlScalar(ctx context.Context, db *sql.DB, query string) (string, bool, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return "", false, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", false, err
+	}
+	if !rows.Next() || len(cols) == 0 {
+		return "", false, rows.Err()
+	}
+	values := make([]sql.NullString, len(cols))
+	dest := make([]any, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", false, err
+	}
+	return values[0].String, values[0].Valid, nil
+}
+
+// checkMongoDB conecta com o driver oficial, faz o ping no primário e executa o comando configurado
+func checkMongoDB(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := databaseConfig(e)
+
+	// endpoints salvos antes da validação de somente leitura também são recusados aqui
+	if err := validateReadOnly(e.MonitorType(), cfg.Query); err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	var cmd bson.D
+	if err := bson.UnmarshalExtJSON([]byte(cfg.Query), false, &cmd); err != nil {
+		result.ErrorMessage = fmt.Sprintf("database.query inválido: %v", err)
+		return result
+	}
+
+	opts := options.Client().
+		SetHosts([]string{databaseAddr(e)}).
+		SetDirect(true).
+		SetConnectTimeout(Timeout(e)).
+		SetServerSelectionTimeout(Timeout(e))
+	if user, password := databaseCredentials(e); user != "" {
+		opts.SetAuth(options.Credential{Username: user, Password: password, AuthSource: cfg.Database})
+	}
+	if cfg.TLS {
+		opts.SetTLSConfig(&tls.Config{ServerName: hostOnly(e.Domain), MinVersion: tls.VersionTLS12})
+	}
+
+	start := time.Now()
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("erro ao conectar: %v", err)
+		return result
+	}
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	if err := client.Ping(ctx, readpref.PrimaryPreferred()); err != nil {
+		result.ResponseTime = time.Since(start)
+		result.ErrorMessage = fmt.Sprintf("erro ao conectar: %v", err)
+		return result
+	}
+	connectTime := time.Since(start)
+	result.Metrics["connect_ms"] = milliseconds(connectTime)
+
+	database := cfg.Database
+	if database == "" {
+		database = "admin"
+	}
+	queryStart := time.Now()
+	var reply bson.D
+	err = client.Database(database).RunCommand(ctx, cmd).Decode(&reply)
+	queryTime := time.Since(queryStart)
+	result.Metrics["query_ms"] = milliseconds(queryTime)
+	result.ResponseTime = connectTime + queryTime
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("erro no comando: %v", err)
+		return result
+	}
+
+	// o escalar é o primeiro campo da resposta, ex.: ok no ping ou n no count
+	if len(reply) == 0 {
+		return finishDatabaseCheck(result, cfg, "", false)
+	}
+	return finishDatabaseCheck(result, cfg, fmt.Sprint(reply[0].Value), true)
+}
+
+// checkRedis conecta (com AUTH e SELECT), mede a conexão e executa o comando configurado
+func checkRedis(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := databaseConfig(e)
+	if err := validateReadOnly(e.MonitorType(), cfg.Query); err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	user, password := databaseCredentials(e)
+	opts := &redis.Options{
+		Addr:        databaseAddr(e),
+		Username:    user,
+		Password:    password,
+		DialTimeout: Timeout(e),
+		PoolSize:    1,
+	}
+	if cfg.Database != "" {
+		opts.DB, _ = strconv.Atoi(cfg.Database)
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{ServerName: hostOnly(e.Domain), MinVersion: tls.VersionTLS12}
+	}
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	// a conexão é aberta no primeiro comando; o PING isola o tempo de conexão do comando configurado
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		result.ResponseTime = time.Since(start)
+		result.ErrorMessage = fmt.Sprintf("erro ao conectar: %v", err)
+		return result
+	}
+	connectTime := time.Since(start)
+	result.Metrics["connect_ms"] = milliseconds(connectTime)
+
+	fields := strings.Fields(cfg.Query)
+	args := make([]any, len(fields))
+	for i, f := range fields {
+		args[i] = f
+	}
+	queryStart := time.Now()
+	reply, err := client.Do(ctx, args...).Result()
+	queryTime := time.Since(queryStart)
+	result.Metrics["query_ms"] = milliseconds(queryTime)
+	result.ResponseTime = connectTime + queryTime
+	if errors.Is(err, redis.Nil) {
+		return finishDatabaseCheck(result, cfg, "", false)
+	}
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("erro no comando: %v", err)
+		return result
+	}
+	return finishDatabaseCheck(result, cfg, fmt.Sprint(reply), true)
+}
+
+// finishDatabaseCheck aplica o expect ao valor retornado; sem expect basta a consulta ter executado
+func finishDatabaseCheck(result CheckResult, cfg entities.DatabaseCheck, value string, exists bool) CheckResult {
+	if cfg.Expect == "" && cfg.Operator == "" {
+		result.Status = entities.StatusOnline
+		return result
+	}
+
+	a := databaseAssertion(cfg)
+	passed, message := compare(a, value, exists)
+	result.Assertions = []entities.AssertionResult{{Assertion: a, Passed: passed, Actual: truncateBanner(value), Message: message}}
+	if !passed {
+		result.ErrorMessage = message
+		return result
+	}
+	result.Status = entities.StatusOnline
+	return result
+}
@@ -0,0 +1,244 @@
+package monitors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/miekg/dns"
+)
+
+// defaultResolver é usado quando o endpoint não define resolver e /etc/resolv.conf não está disponível
+const defaultResolver = "1.1.1.1:53"
+
+// dnsRecordTypes são os registros suportados pelo monitor dns
+var dnsRecordTypes = map[string]uint16{
+	"A":     dns.TypeA,
+	"AAAA":  dns.TypeAAAA,
+	"CNAME": dns.TypeCNAME,
+	"MX":    dns.TypeMX,
+	"TXT":   dns.TypeTXT,
+	"NS":    dns.TypeNS,
+	"SRV":   dns.TypeSRV,
+	"CAA":   dns.TypeCAA,
+}
+
+func validateDNS(e *entities.Endpoint) error {
+	cfg := e.DNS
+	if cfg == nil {
+		return errors.New("dns é obrigatório para monitores dns")
+	}
+	if _, ok := dnsRecordTypes[strings.ToUpper(cfg.RecordType)]; !ok {
+		return fmt.Errorf("dns.record_type inválido: %s", cfg.RecordType)
+	}
+	switch cfg.Protocol {
+	case "", "udp", "tcp":
+	case "doh":
+		if !strings.HasPrefix(cfg.Resolver, "https://") {
+			return errors.New("dns.resolver deve ser uma URL https:// para doh")
+		}
+	default:
+		return fmt.Errorf("dns.protocol inválido: %s", cfg.Protocol)
+	}
+	if cfg.ExpectRegex != "" {
+		if _, err := regexp.Compile(cfg.ExpectRegex); err != nil {
+			return fmt.Errorf("dns.expect_regex inválido: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkDNS consulta o registro configurado e valida rcode, respostas e tempo de resolução
+func checkDNS(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := e.DNS
+	if cfg == nil {
+		result.ErrorMessage = "configuração dns ausente"
+		return result
+	}
+	qtype := dnsRecordTypes[strings.ToUpper(cfg.RecordType)]
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(hostOnly(e.Domain)), qtype)
+	msg.RecursionDesired = true
+
+	start := time.Now()
+	resp, err := exchangeDNS(ctx, msg, cfg)
+	result.ResponseTime = time.Since(start)
+	result.Metrics["resolve_ms"] = milliseconds(result.ResponseTime)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		result.ErrorMessage = "NXDOMAIN: o nome não existe"
+		return result
+	default:
+		result.ErrorMessage = fmt.Sprintf("resolver respondeu %s", dns.RcodeToString[resp.Rcode])
+		return result
+	}
+
+	answers := dnsAnswers(resp, qtype)
+	result.Answers = answers
+	result.Metrics["answers"] = float64(len(answers))
+
+	if len(answers) == 0 {
+		result.ErrorMessage = fmt.Sprintf("nenhum registro %s encontrado", strings.ToUpper(cfg.RecordType))
+		return result
+	}
+	if len(cfg.Expected) > 0 {
+		expected := normalizeAnswers(cfg.Expected, qtype)
+		if !slices.Equal(expected, answers) {
+			result.ErrorMessage = fmt.Sprintf("respostas inesperadas: %s (esperado %s)", strings.Join(answers, ", "), strings.Join(expected, ", "))
+			return result
+		}
+	}
+	if cfg.ExpectRegex != "" {
+		re, err := regexp.Compile(cfg.ExpectRegex)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("dns.expect_regex inválido: %v", err)
+			return result
+		}
+		for _, a := range answers {
+			if !re.MatchString(a) {
+				result.ErrorMessage = fmt.Sprintf("resposta %q não corresponde a %s", a, cfg.ExpectRegex)
+				return result
+			}
+		}
+	}
+	if cfg.MaxResponseTime > 0 && result.ResponseTime > time.Duration(cfg.MaxResponseTime)*time.Millisecond {
+		result.ErrorMessage = fmt.Sprintf("resolução lenta: %d ms (máximo %d ms)", result.ResponseTime.Milliseconds(), cfg.MaxResponseTime)
+		return result
+	}
+
+	result.Status = entities.StatusOnline
+	return result
+}
+
+// exchangeDNS envia a consulta via udp/tcp ou DoH (RFC 8484); respostas udp truncadas são repetidas via tcp
+func exchangeDNS(ctx context.Context, msg *dns.Msg, cfg *entities.DNSCheck) (*dns.Msg, error) {
+	if cfg.Protocol == "doh" {
+		return exchangeDoH(ctx, msg, cfg.Resolver)
+	}
+
+	resolver := cfg.Resolver
+	if resolver == "" {
+		resolver = systemResolver()
+	} else if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(strings.Trim(resolver, "[]"), "53")
+	}
+
+	proto := cfg.Protocol
+	if proto == "" {
+		proto = "udp"
+	}
+	client := &dns.Client{Net: proto}
+	resp, _, err := client.ExchangeContext(ctx, msg, resolver)
+	if err == nil && resp.Truncated && proto == "udp" {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, resolver)
+	}
+	return resp, err
+}
+
+func exchangeDoH(ctx context.Context, msg *dns.Msg, resolver string) (*dns.Msg, error) {
+	// RFC 8484 recomenda id 0 para melhorar o cache HTTP
+	msg.Id = 0
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, resolver, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("resolver DoH respondeu HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+	answer := new(dns.Msg)
+	if err := answer.Unpack(body); err != nil {
+		return nil, fmt.Errorf("resposta DoH inválida: %w", err)
+	}
+	return answer, nil
+}
+
+// systemResolver usa o primeiro nameserver de /etc/resolv.conf
+func systemResolver() string {
+	conf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(conf.Servers) == 0 {
+		return defaultResolver
+	}
+	return net.JoinHostPort(conf.Servers[0], conf.Port)
+}
+
+// dnsAnswers extrai os valores dos registros do tipo consultado, normalizados e ordenados
+func dnsAnswers(resp *dns.Msg, qtype uint16) []string {
+	var answers []string
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+		switch r := rr.(type) {
+		case *dns.A:
+			answers = append(answers, r.A.String())
+		case *dns.AAAA:
+			answers = append(answers, r.AAAA.String())
+		case *dns.CNAME:
+			answers = append(answers, r.Target)
+		case *dns.MX:
+			answers = append(answers, strconv.Itoa(int(r.Preference))+" "+r.Mx)
+		case *dns.TXT:
+			answers = append(answers, strings.Join(r.Txt, ""))
+		case *dns.NS:
+			answers = append(answers, r.Ns)
+		case *dns.SRV:
+			answers = append(answers, fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Target))
+		case *dns.CAA:
+			answers = append(answers, fmt.Sprintf("%d %s %s", r.Flag, r.Tag, r.Value))
+		}
+	}
+	return normalizeAnswers(answers, qtype)
+}
+
+// normalizeAnswers ordena as respostas para comparar conjuntos. Nos tipos cujo valor é um endereço ou
+// termina em nome de domínio remove o ponto final e ignora caixa; TXT e CAA diferenciam maiúsculas
+// (chaves DKIM, tokens de verificação) e são comparados como vieram
+func normalizeAnswers(answers []string, qtype uint16) []string {
+	normalized := make([]string, 0, len(answers))
+	for _, a := range answers {
+		a = strings.TrimSpace(a)
+		if qtype != dns.TypeTXT && qtype != dns.TypeCAA {
+			a = strings.ToLower(strings.TrimSuffix(a, "."))
+		}
+		normalized = append(normalized, a)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
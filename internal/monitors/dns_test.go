@@ -0,0 +1,33 @@
+package monitors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestNormalizeAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []string
+		qtype   uint16
+		want    []string
+	}{
+		{"cname sem ponto final e minúsculo", []string{"Edge.Example.COM."}, dns.TypeCNAME, []string{"edge.example.com"}},
+		{"mx ordenado", []string{"20 MX2.example.com.", "10 mx1.example.com."}, dns.TypeMX, []string{"10 mx1.example.com", "20 mx2.example.com"}},
+		{"aaaa minúsculo", []string{"2001:DB8::1"}, dns.TypeAAAA, []string{"2001:db8::1"}},
+		{"duplicados removidos", []string{"192.0.2.1", " 192.0.2.1 "}, dns.TypeA, []string{"192.0.2.1"}},
+		{"txt mantém caixa", []string{"v=DKIM1; p=MIGfMA0GCSqGSIb3"}, dns.TypeTXT, []string{"v=DKIM1; p=MIGfMA0GCSqGSIb3"}},
+		{"txt mantém ponto final", []string{"token. "}, dns.TypeTXT, []string{"token."}},
+		{"caa mantém caixa", []string{"0 issue LetsEncrypt.org"}, dns.TypeCAA, []string{"0 issue LetsEncrypt.org"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeAnswers(tt.answers, tt.qtype); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeAnswers(%q) = %q, want %q", tt.answers, got, tt.want)
+			}
+		})
+	}
+}
@@ -1 +1,197 @@
 package monitors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// maxBodySize limita quanto do body é mantido em memória para as assertions
+	maxBodySize = 1 << 20
+)
+
+// CheckResult - Resultado de uma verificação de endpoint
+type CheckResult struct {
+	Status       entities.EndpointStatus
+	StatusCode   int
+	ResponseTime time.Duration
+	ErrorMessage string
+	Metrics      map[string]float64
+	Answers      []string // respostas observadas pelos monitores dns
+	Assertions   []entities.AssertionResult
+	Steps        []entities.StepResult
+	Timings      *entities.RequestTimings
+	GRPCCode     string // código de status da chamada de health (grpc)
+	Output       string // primeira linha da saída do plugin (exec) ou banner (smtp, imap, pop3)
+	CheckedAt    time.Time
+}
+
+// CheckEndpoint executa o health check do endpoint de acordo com o seu tipo
+func CheckEndpoint(ctx context.Context, e *entities.Endpoint) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, Timeout(e))
+	defer cancel()
+
+	switch e.MonitorType() {
+	case entities.TypeHTTP:
+		return checkHTTP(ctx, e)
+	case entities.TypeTCP:
+		return checkTCP(ctx, e)
+	case entities.TypeDNS:
+		return checkDNS(ctx, e)
+	case entities.TypeGRPC:
+		return checkGRPC(ctx, e)
+	case entities.TypeWebSocket:
+		return checkWebSocket(ctx, e)
+	case entities.TypeSMTP, entities.TypeIMAP, entities.TypePOP3:
+		return checkMail(ctx, e)
+	case entities.TypePostgres, entities.TypeMySQL:
+		return checkSQL(ctx, e)
+	case entities.TypeMongoDB:
+		return checkMongoDB(ctx, e)
+	case entities.TypeRedis:
+		return checkRedis(ctx, e)
+	case entities.TypeExec:
+		return checkExec(ctx, e)
+	case entities.TypeStarlark:
+		result, _ := runStarlark(ctx, e)
+		return result
+	case entities.TypeHeartbeat:
+		return checkHeartbeat(e)
+	case entities.TypeScenario:
+		return checkScenario(ctx, e)
+	default:
+		result := newResult()
+		result.Status = entities.StatusUnknown
+		result.ErrorMessage = fmt.Sprintf("tipo de monitor desconhecido: %s", e.Type)
+		return result
+	}
+}
+
+// ValidateEndpoint verifica a configuração específica do tipo de monitor
+func ValidateEndpoint(e *entities.Endpoint) error {
+	if !e.MonitorType().IsValid() {
+		return fmt.Errorf("type inválido: %s", e.Type)
+	}
+	if err := validateSSL(e.SSL); err != nil {
+		return err
+	}
+	if err := validateDomainCheck(e); err != nil {
+		return err
+	}
+
+	switch e.MonitorType() {
+	case entities.TypeHTTP:
+		if err := validateAuth(e.Authentication); err != nil {
+			return err
+		}
+		return validateAssertions(e.Assertions)
+	case entities.TypeTCP:
+		return validateTCP(e)
+	case entities.TypeDNS:
+		return validateDNS(e)
+	case entities.TypeGRPC:
+		return validateGRPC(e)
+	case entities.TypeWebSocket:
+		return validateWebSocket(e)
+	case entities.TypeSMTP, entities.TypeIMAP, entities.TypePOP3:
+		return validateMail(e)
+	case entities.TypePostgres, entities.TypeMySQL, entities.TypeMongoDB, entities.TypeRedis:
+		return validateDatabase(e)
+	case entities.TypeExec:
+		return validateExec(e)
+	case entities.TypeStarlark:
+		return validateStarlark(e)
+	case entities.TypeHeartbeat:
+		return validateHeartbeat(e)
+	case entities.TypeScenario:
+		return validateScenario(e)
+	}
+	return nil
+}
+
+// Timeout devolve o timeout configurado no endpoint (segundos) ou o padrão de 30s
+func Timeout(e *entities.Endpoint) time.Duration {
+	if e.Timeout > 0 {
+		return time.Duration(e.Timeout) * time.Second
+	}
+	return defaultTimeout
+}
+
+func newResult() CheckResult {
+	return CheckResult{
+		Status:    entities.StatusOffline,
+		Metrics:   make(map[string]float64),
+		CheckedAt: time.Now().UTC(),
+	}
+}
+
+// checkHTTP faz um GET na URL do endpoint; status >= 400 ou assertions com falha deixam o endpoint offline
+func checkHTTP(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.URL(), nil)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "Ratatoskr/1.0")
+
+	client, err := httpClient(e.Authentication)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	if err := applyAuth(req, e.Authentication); err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	req, tracer := withTrace(req)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.ResponseTime = time.Since(start)
+		result.Timings = tracer.timings(time.Now())
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	if len(e.Assertions) > 0 {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	}
+	rest, _ := io.Copy(io.Discard, resp.Body)
+	result.ResponseTime = time.Since(start)
+	result.Timings = tracer.timings(time.Now())
+	result.StatusCode = resp.StatusCode
+
+	var failures []string
+	if resp.StatusCode >= http.StatusBadRequest {
+		failures = append(failures, fmt.Sprintf("status HTTP inesperado: %d", resp.StatusCode))
+	}
+	if len(e.Assertions) > 0 {
+		result.Assertions = evaluateAssertions(e.Assertions, resp, body, int64(len(body))+rest, result.ResponseTime)
+		failures = append(failures, assertionFailures(result.Assertions)...)
+	}
+
+	if len(failures) > 0 {
+		result.ErrorMessage = strings.Join(failures, "; ")
+		return result
+	}
+
+	result.Status = entities.StatusOnline
+	return result
+}
+
+// milliseconds converte a duração para métricas em ms com casas decimais
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,255 @@
+package monitors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+// maxExecOutput limita quanto da saída do plugin é lido
+const maxExecOutput = 64 * 1024
+
+var (
+	execMu sync.RWMutex
+	// execAllowlist contém os comandos (ou padrões glob) que o worker pode executar; vazia desabilita o exec
+	execAllowlist []string
+)
+
+// AllowExec habilita os monitores exec neste processo para os comandos informados
+func AllowExec(commands []string) {
+	execMu.Lock()
+	defer execMu.Unlock()
+	execAllowlist = nil
+	for _, c := range commands {
+		if c = strings.TrimSpace(c); c != "" {
+			execAllowlist = append(execAllowlist, filepath.Clean(c))
+		}
+	}
+}
+
+// ExecEnabled indica se este worker executa monitores exec
+func ExecEnabled() bool {
+	execMu.RLock()
+	defer execMu.RUnlock()
+	return len(execAllowlist) > 0
+}
+
+func execAllowed(command string) bool {
+	execMu.RLock()
+	defer execMu.RUnlock()
+	command = filepath.Clean(command)
+	for _, pattern := range execAllowlist {
+		if ok, _ := filepath.Match(pattern, command); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateExec valida apenas a forma; a allowlist é do worker e é aplicada na execução
+func validateExec(e *entities.Endpoint) error {
+	if e.Exec == nil || strings.TrimSpace(e.Exec.Command) == "" {
+		return errors.New("exec.command é obrigatório para monitores exec")
+	}
+	if !filepath.IsAbs(e.Exec.Command) {
+		return errors.New("exec.command deve ser um caminho absoluto")
+	}
+	for k := range e.Exec.Env {
+		if k == "" || strings.ContainsAny(k, "=\x00") {
+			return fmt.Errorf("exec.env: nome de variável inválido %q", k)
+		}
+		if execReservedEnv(k) {
+			return fmt.Errorf("exec.env: a variável %s não pode ser definida pelo endpoint", k)
+		}
+	}
+	return nil
+}
+
+// checkExec executa o plugin sob o Timeout do endpoint e interpreta o código de saída e a saída
+// no formato do Nagios: "TEXTO | perfdata", seguida opcionalmente de mais linhas e perfdata.
+func checkExec(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	result.Status = entities.StatusUnknown
+	if err := validateExec(e); err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	if !execAllowed(e.Exec.Command) {
+		result.ErrorMessage = fmt.Sprintf("comando não permitido neste worker: %s", e.Exec.Command)
+		return result
+	}
+
+	cmd := exec.CommandContext(ctx, e.Exec.Command, e.Exec.Args...)
+	cmd.Env = execEnv(e.Exec.Env)
+	cmd.WaitDelay = time.Second // não espera processos filhos que herdaram a saída
+	var out bytes.Buffer
+	cmd.Stdout = &limitedWriter{w: &out, n: maxExecOutput}
+	cmd.Stderr = cmd.Stdout
+
+	start := time.Now()
+	err := cmd.Run()
+	result.ResponseTime = time.Since(start)
+	result.Metrics["duration_ms"] = milliseconds(result.ResponseTime)
+
+	text, perfdata := parsePluginOutput(out.String())
+	result.Output = text
+	for k, v := range perfdata {
+		result.Metrics[k] = v
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		// como no Nagios, o plugin que estoura o timeout é tratado como CRITICAL
+		result.Status = entities.StatusOffline
+		result.ErrorMessage = fmt.Sprintf("timeout após %s", Timeout(e))
+		return result
+	case err == nil:
+		result.Status = entities.StatusOnline
+		return result
+	case errors.As(err, &exitErr):
+		switch exitErr.ExitCode() {
+		case 1:
+			result.Status = entities.StatusDegraded
+		case 2:
+			result.Status = entities.StatusOffline
+		}
+	}
+
+	result.ErrorMessage = text
+	if result.ErrorMessage == "" {
+		result.ErrorMessage = err.Error()
+	}
+	return result
+}
+
+// execReservedEnv indica as variáveis que mudam o que é executado (PATH, loader dinâmico, arquivos de
+// inicialização do shell) e permitiriam rodar outro código por meio de um plugin da allowlist
+func execReservedEnv(name string) bool {
+	upper := strings.ToUpper(name)
+	switch upper {
+	case "PATH", "IFS", "ENV", "BASH_ENV", "SHELLOPTS", "BASHOPTS", "PS4":
+		return true
+	}
+	return strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_") || strings.HasPrefix(upper, "BASH_FUNC_")
+}
+
+// execEnv monta um ambiente mínimo (PATH e LANG do worker) mais as variáveis do endpoint,
+// para não repassar ao plugin o ambiente do worker
+func execEnv(extra map[string]string) []string {
+	env := []string{"PATH=" + os.Getenv("PATH"), "LANG=C"}
+	for k, v := range extra {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// parsePluginOutput devolve o texto da primeira linha e as métricas do perfdata (primeira linha e,
+// depois do primeiro "|" nas linhas seguintes, o perfdata longo)
+func parsePluginOutput(output string) (string, map[string]float64) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	first, perf, _ := strings.Cut(lines[0], "|")
+
+	var long []string
+	inPerf := false
+	for _, line := range lines[1:] {
+		if !inPerf {
+			var found bool
+			if _, line, found = strings.Cut(line, "|"); !found {
+				continue
+			}
+			inPerf = true
+		}
+		long = append(long, line)
+	}
+	if len(long) > 0 {
+		perf += " " + strings.Join(long, " ")
+	}
+	return truncateBanner(strings.TrimSpace(first)), parsePerfdata(perf)
+}
+
+// parsePerfdata interpreta 'label'=valor[UOM];warn;crit;min;max. Tempos (s, ms, us) são convertidos
+// para milissegundos com o sufixo _ms no nome; as demais unidades são mantidas como vieram.
+func parsePerfdata(perf string) map[string]float64 {
+	metrics := make(map[string]float64)
+	for _, item := range splitPerfdata(perf) {
+		label, data, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		label = metricLabel.Replace(strings.ReplaceAll(strings.Trim(label, "'"), "''", "'"))
+		value, _, _ := strings.Cut(data, ";")
+
+		end := strings.LastIndexAny(value, "0123456789.") + 1
+		n, err := strconv.ParseFloat(strings.Replace(value[:end], ",", ".", 1), 64)
+		if label == "" || err != nil {
+			continue
+		}
+		switch value[end:] {
+		case "s":
+			metrics[label+"_ms"] = n * 1000
+		case "ms":
+			metrics[label+"_ms"] = n
+		case "us":
+			metrics[label+"_ms"] = n / 1000
+		default:
+			metrics[label] = n
+		}
+	}
+	return metrics
+}
+
+// metricLabel troca os caracteres que o MongoDB não aceita bem em chaves de documento
+var metricLabel = strings.NewReplacer(".", "_", "$", "_")
+
+// splitPerfdata separa os itens por espaço, respeitando labels entre aspas simples
+func splitPerfdata(perf string) []string {
+	var items []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range perf {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if current.Len() > 0 {
+				items = append(items, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		items = append(items, current.String())
+	}
+	return items
+}
+
+// limitedWriter descarta o que passar de n bytes sem falhar a escrita do plugin
+type limitedWriter struct {
+	w *bytes.Buffer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if remaining := l.n - l.w.Len(); remaining > 0 {
+		if len(p) > remaining {
+			l.w.Write(p[:remaining])
+		} else {
+			l.w.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,91 @@
+package monitors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+func TestParsePerfdata(t *testing.T) {
+	tests := []struct {
+		name string
+		perf string
+		want map[string]float64
+	}{
+		{"vazio", "", map[string]float64{}},
+		{"valor sem unidade", "users=12;20;50;0", map[string]float64{"users": 12}},
+		{"segundos viram ms", "time=0.25s;1;2", map[string]float64{"time_ms": 250}},
+		{"ms mantidos", "rta=12.5ms", map[string]float64{"rta_ms": 12.5}},
+		{"microssegundos", "lat=1500us", map[string]float64{"lat_ms": 1.5}},
+		{"outras unidades mantêm o nome", "size=512B used=80%", map[string]float64{"size": 512, "used": 80}},
+		{"label com espaço e aspas", "'disk usage'=42% 'it''s'=1", map[string]float64{"disk usage": 42, "it's": 1}},
+		{"ponto e cifrão trocados", "db.conn$x=3", map[string]float64{"db_conn_x": 3}},
+		{"vírgula decimal", "load=1,5", map[string]float64{"load": 1.5}},
+		{"itens inválidos ignorados", "semvalor= =3 ok=1 x=abc", map[string]float64{"ok": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePerfdata(tt.perf); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePerfdata(%q) = %v, want %v", tt.perf, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePluginOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		wantText string
+		wantPerf map[string]float64
+	}{
+		{"sem perfdata", "OK - tudo certo\n", "OK - tudo certo", map[string]float64{}},
+		{"perfdata na primeira linha", "OK - 3 usuários | users=3;5;10", "OK - 3 usuários", map[string]float64{"users": 3}},
+		{
+			name:     "perfdata longo",
+			output:   "WARNING - carga alta | load1=5\ndetalhe 1\ndetalhe 2 | load5=4\nload15=3\n",
+			wantText: "WARNING - carga alta",
+			wantPerf: map[string]float64{"load1": 5, "load5": 4, "load15": 3},
+		},
+		{"saída vazia", "", "", map[string]float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, perf := parsePluginOutput(tt.output)
+			if text != tt.wantText || !reflect.DeepEqual(perf, tt.wantPerf) {
+				t.Errorf("parsePluginOutput() = %q, %v; want %q, %v", text, perf, tt.wantText, tt.wantPerf)
+			}
+		})
+	}
+}
+
+func TestValidateExecEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"sem variáveis", nil, false},
+		{"variável comum", map[string]string{"CHECK_HOST": "db"}, false},
+		{"PATH", map[string]string{"PATH": "/tmp"}, true},
+		{"path minúsculo", map[string]string{"path": "/tmp"}, true},
+		{"LD_PRELOAD", map[string]string{"LD_PRELOAD": "/tmp/x.so"}, true},
+		{"LD_LIBRARY_PATH", map[string]string{"LD_LIBRARY_PATH": "/tmp"}, true},
+		{"BASH_ENV", map[string]string{"BASH_ENV": "/tmp/x.sh"}, true},
+		{"ENV", map[string]string{"ENV": "/tmp/x.sh"}, true},
+		{"IFS", map[string]string{"IFS": "/"}, true},
+		{"nome inválido", map[string]string{"A=B": "x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &entities.Endpoint{Type: entities.TypeExec, Exec: &entities.ExecCheck{Command: "/usr/lib/nagios/plugins/check_users", Env: tt.env}}
+			if err := validateExec(e); (err != nil) != tt.wantErr {
+				t.Errorf("validateExec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
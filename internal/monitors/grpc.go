@@ -0,0 +1,113 @@
+package monitors
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func validateGRPC(e *entities.Endpoint) error {
+	if e.Port <= 0 || e.Port > 65535 {
+		return errors.New("port é obrigatório para monitores grpc")
+	}
+	if e.Authentication != nil {
+		if e.Authentication.Type != entities.AuthMTLS {
+			return errors.New("monitores grpc aceitam apenas authentication do tipo mtls; use grpc.metadata para tokens")
+		}
+		if err := validateAuth(e.Authentication); err != nil {
+			return err
+		}
+	}
+	if e.GRPC != nil {
+		for k := range e.GRPC.Metadata {
+			// pseudo-headers (:authority) e o prefixo grpc- são reservados pelo protocolo
+			if k == "" || strings.HasPrefix(k, ":") || strings.HasPrefix(strings.ToLower(k), "grpc-") {
+				return fmt.Errorf("grpc.metadata: chave reservada ou inválida %q", k)
+			}
+		}
+	}
+	return nil
+}
+
+// checkGRPC executa o protocolo de health check do gRPC: SERVING é online; NOT_SERVING, UNKNOWN ou erro
+// na chamada são offline, com o código de status guardado no histórico
+func checkGRPC(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := e.GRPC
+	if cfg == nil {
+		cfg = &entities.GRPCCheck{}
+	}
+
+	creds, err := grpcCredentials(e, cfg)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	addr := net.JoinHostPort(hostOnly(e.Domain), strconv.Itoa(e.Port))
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds), grpc.WithUserAgent("Ratatoskr/1.0"))
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	defer conn.Close()
+
+	if len(cfg.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(cfg.Metadata))
+	}
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: cfg.Service})
+	result.ResponseTime = time.Since(start)
+	result.Metrics["response_ms"] = milliseconds(result.ResponseTime)
+	if err != nil {
+		st := status.Convert(err)
+		result.GRPCCode = st.Code().String()
+		result.ErrorMessage = fmt.Sprintf("grpc %s: %s", st.Code(), st.Message())
+		return result
+	}
+
+	result.GRPCCode = codes.OK.String()
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		result.ErrorMessage = fmt.Sprintf("health check respondeu %s", resp.GetStatus())
+		return result
+	}
+	result.Status = entities.StatusOnline
+	return result
+}
+
+// grpcCredentials devolve plaintext, TLS ou mTLS (authentication mtls) conforme a configuração
+func grpcCredentials(e *entities.Endpoint, cfg *entities.GRPCCheck) (credentials.TransportCredentials, error) {
+	mtls := e.Authentication != nil && e.Authentication.Type == entities.AuthMTLS
+	if !cfg.TLS && !mtls {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if mtls {
+		var err error
+		if tlsConfig, err = mtlsConfig(e.Authentication); err != nil {
+			return nil, err
+		}
+	}
+	tlsConfig.ServerName = hostOnly(e.Domain)
+	if cfg.ServerName != "" {
+		tlsConfig.ServerName = cfg.ServerName
+	}
+	tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+	return credentials.NewTLS(tlsConfig), nil
+}
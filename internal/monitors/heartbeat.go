@@ -0,0 +1,126 @@
+package monitors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/robfig/cron/v3"
+)
+
+// NewHeartbeatToken gera o token secreto usado na URL de ping
+func NewHeartbeatToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validateHeartbeat(e *entities.Endpoint) error {
+	cfg := e.Heartbeat
+	if cfg == nil {
+		return errors.New("heartbeat é obrigatório para monitores heartbeat")
+	}
+	if (cfg.Period > 0) == (cfg.Cron != "") {
+		return errors.New("informe heartbeat.period ou heartbeat.cron")
+	}
+	if cfg.Period < 0 || cfg.Grace < 0 {
+		return errors.New("heartbeat.period e heartbeat.grace não podem ser negativos")
+	}
+	if cfg.Cron != "" {
+		if _, err := cron.ParseStandard(cfg.Cron); err != nil {
+			return fmt.Errorf("heartbeat.cron inválido: %w", err)
+		}
+	}
+	if cfg.TimeZone != "" {
+		if _, err := time.LoadLocation(cfg.TimeZone); err != nil {
+			return fmt.Errorf("heartbeat.timezone inválido: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkHeartbeat avalia os pings já recebidos: falha reportada pelo job ou ping atrasado deixam o endpoint offline
+func checkHeartbeat(e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := e.Heartbeat
+	if cfg == nil {
+		result.ErrorMessage = "configuração heartbeat ausente"
+		return result
+	}
+
+	var pings entities.HeartbeatState
+	if e.Pings != nil {
+		pings = *e.Pings
+	}
+	now := result.CheckedAt
+
+	if !pings.LastFail.IsZero() && pings.LastFail.After(pings.LastSuccess) {
+		result.ErrorMessage = fmt.Sprintf("job reportou falha (exit code %d)", pings.ExitCode)
+		if pings.Message != "" {
+			result.ErrorMessage += ": " + truncateBanner(pings.Message)
+		}
+		return result
+	}
+
+	// sem ping ainda, o prazo conta a partir da criação do endpoint
+	last := pings.LastSuccess
+	if last.IsZero() {
+		last = e.CreatedAt
+	}
+	if last.IsZero() {
+		result.Status = entities.StatusUnknown
+		result.ErrorMessage = "aguardando o primeiro ping"
+		return result
+	}
+
+	deadline, err := heartbeatDeadline(cfg, last)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	if !pings.LastSuccess.IsZero() {
+		result.Metrics["since_last_ping_s"] = now.Sub(pings.LastSuccess).Seconds()
+		if pings.LastStart.Before(pings.LastSuccess) && !pings.LastStart.IsZero() {
+			result.ResponseTime = pings.LastSuccess.Sub(pings.LastStart)
+			result.Metrics["duration_ms"] = milliseconds(result.ResponseTime)
+		}
+	}
+
+	if now.After(deadline) {
+		if pings.LastSuccess.IsZero() {
+			result.ErrorMessage = fmt.Sprintf("nenhum ping recebido (esperado até %s)", deadline.Format(time.RFC3339))
+		} else {
+			result.ErrorMessage = fmt.Sprintf("ping atrasado: último em %s, esperado até %s", pings.LastSuccess.Format(time.RFC3339), deadline.Format(time.RFC3339))
+		}
+		return result
+	}
+
+	result.Status = entities.StatusOnline
+	return result
+}
+
+// heartbeatDeadline calcula até quando o próximo ping deve chegar, já somando a tolerância
+func heartbeatDeadline(cfg *entities.HeartbeatCheck, last time.Time) (time.Time, error) {
+	grace := time.Duration(cfg.Grace) * time.Second
+	if cfg.Cron == "" {
+		return last.Add(time.Duration(cfg.Period)*time.Second + grace), nil
+	}
+
+	schedule, err := cron.ParseStandard(cfg.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("heartbeat.cron inválido: %w", err)
+	}
+	loc := time.UTC
+	if cfg.TimeZone != "" {
+		if loc, err = time.LoadLocation(cfg.TimeZone); err != nil {
+			return time.Time{}, fmt.Errorf("heartbeat.timezone inválido: %w", err)
+		}
+	}
+	return schedule.Next(last.In(loc)).Add(grace), nil
+}
@@ -0,0 +1,415 @@
+package monitors
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+// mailProtocol reúne os comandos de cada protocolo de e-mail; capabilities devolve as capabilities já
+// normalizadas (maiúsculas, mecanismos como AUTH=MECANISMO)
+type mailProtocol struct {
+	port, tlsPort int
+	greet         func(tp *textproto.Conn) (string, error)
+	capabilities  func(tp *textproto.Conn, hostname string) (map[string]bool, error)
+	startTLS      func(tp *textproto.Conn) error
+	login         func(tp *textproto.Conn, user, password string, caps map[string]bool) error
+	quit          string
+}
+
+var mailProtocols = map[entities.EndpointType]mailProtocol{
+	entities.TypeSMTP: {port: 25, tlsPort: 465, greet: smtpGreet, capabilities: smtpCapabilities, startTLS: smtpStartTLS, login: smtpLogin, quit: "QUIT"},
+	entities.TypeIMAP: {port: 143, tlsPort: 993, greet: imapGreet, capabilities: imapCapabilities, startTLS: imapStartTLS, login: imapLogin, quit: "a9 LOGOUT"},
+	entities.TypePOP3: {port: 110, tlsPort: 995, greet: pop3Greet, capabilities: pop3Capabilities, startTLS: pop3StartTLS, login: pop3Login, quit: "QUIT"},
+}
+
+func validateMail(e *entities.Endpoint) error {
+	if e.Port < 0 || e.Port > 65535 {
+		return errors.New("port inválido")
+	}
+	if e.Authentication != nil {
+		if e.Authentication.Type != entities.AuthBasic {
+			return fmt.Errorf("monitores %s aceitam apenas authentication do tipo basic", e.MonitorType())
+		}
+		if err := validateAuth(e.Authentication); err != nil {
+			return err
+		}
+	}
+	cfg := e.Mail
+	if cfg == nil {
+		return nil
+	}
+	if cfg.TLS && cfg.StartTLS {
+		return errors.New("mail.tls e mail.starttls não podem ser usados juntos")
+	}
+	for _, c := range cfg.Capabilities {
+		if strings.TrimSpace(c) == "" || strings.ContainsAny(c, " \r\n") {
+			return fmt.Errorf("mail.capabilities: capability inválida %q", c)
+		}
+	}
+	if cfg.Hostname != "" && strings.ContainsAny(cfg.Hostname, " \r\n") {
+		return errors.New("mail.hostname inválido")
+	}
+	if cfg.Login {
+		if e.Authentication == nil || e.Authentication.Username == "" {
+			return errors.New("mail.login exige authentication do tipo basic com username")
+		}
+		if strings.ContainsAny(e.Authentication.Username+e.Authentication.Password, "\r\n") {
+			return errors.New("authentication não pode conter quebras de linha")
+		}
+		// não envia a senha em texto claro
+		if !cfg.TLS && !cfg.StartTLS {
+			return errors.New("mail.login exige mail.tls ou mail.starttls")
+		}
+	}
+	return nil
+}
+
+// mailAddr usa a porta do endpoint ou a padrão do protocolo: TLS implícito, submissão (587) para smtp
+// com STARTTLS ou a porta sem TLS
+func mailAddr(e *entities.Endpoint, cfg *entities.MailCheck, proto mailProtocol) string {
+	port := e.Port
+	switch {
+	case port > 0:
+	case cfg.TLS:
+		port = proto.tlsPort
+	case cfg.StartTLS:
+		port = startTLSPorts[string(e.MonitorType())]
+	default:
+		port = proto.port
+	}
+	return net.JoinHostPort(hostOnly(e.Domain), strconv.Itoa(port))
+}
+
+// checkMail conecta no servidor (com TLS implícito ou STARTTLS), lê o banner, lista as capabilities e,
+// com Login, autentica, medindo cada fase separadamente
+func checkMail(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := e.Mail
+	if cfg == nil {
+		cfg = &entities.MailCheck{}
+	}
+	proto := mailProtocols[e.MonitorType()]
+	tlsConfig := &tls.Config{ServerName: hostOnly(e.Domain), MinVersion: tls.VersionTLS12}
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname = "ratatoskr"
+	}
+
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", mailAddr(e, cfg, proto))
+	if err != nil {
+		result.ResponseTime = time.Since(start)
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if cfg.TLS {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			result.ResponseTime = time.Since(start)
+			result.ErrorMessage = fmt.Sprintf("erro no handshake TLS: %v", err)
+			return result
+		}
+		conn = tlsConn
+	}
+	result.Metrics["connect_ms"] = milliseconds(time.Since(start))
+	tp := textproto.NewConn(conn)
+
+	banner, err := proto.greet(tp)
+	result.ResponseTime = time.Since(start)
+	result.Metrics["banner_ms"] = milliseconds(result.ResponseTime)
+	result.Output = truncateBanner(banner)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("erro ao ler o banner: %v", err)
+		return result
+	}
+
+	phase := time.Now()
+	caps, err := proto.capabilities(tp, hostname)
+	result.Metrics["capabilities_ms"] = milliseconds(time.Since(phase))
+	result.ResponseTime = time.Since(start)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("erro ao listar capabilities: %v", err)
+		return result
+	}
+
+	if cfg.StartTLS {
+		phase = time.Now()
+		if !caps["STARTTLS"] {
+			result.ErrorMessage = "o servidor não anuncia STARTTLS"
+			return result
+		}
+		if err := proto.startTLS(tp); err != nil {
+			result.ErrorMessage = err.Error()
+			return result
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			result.ResponseTime = time.Since(start)
+			result.ErrorMessage = fmt.Sprintf("erro no handshake TLS: %v", err)
+			return result
+		}
+		tp = textproto.NewConn(tlsConn)
+
+		// as capabilities mudam depois do upgrade (ex.: AUTH só é anunciado sob TLS)
+		after, err := proto.capabilities(tp, hostname)
+		result.Metrics["starttls_ms"] = milliseconds(time.Since(phase))
+		result.ResponseTime = time.Since(start)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("erro ao listar capabilities após STARTTLS: %v", err)
+			return result
+		}
+		for c := range after {
+			caps[c] = true
+		}
+	}
+
+	var missing []string
+	for _, c := range cfg.Capabilities {
+		if !caps[strings.ToUpper(strings.TrimSpace(c))] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		result.ErrorMessage = "capabilities não anunciadas: " + strings.Join(missing, ", ")
+		return result
+	}
+
+	if cfg.Login {
+		phase = time.Now()
+		err := proto.login(tp, e.Authentication.Username, e.Authentication.Password, caps)
+		result.Metrics["login_ms"] = milliseconds(time.Since(phase))
+		result.ResponseTime = time.Since(start)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("erro no login: %v", err)
+			return result
+		}
+	}
+
+	_ = tp.PrintfLine("%s", proto.quit)
+	result.ResponseTime = time.Since(start)
+	result.Status = entities.StatusOnline
+	return result
+}
+
+func smtpGreet(tp *textproto.Conn) (string, error) {
+	code, msg, err := tp.ReadResponse(220)
+	if code == 0 {
+		return "", err
+	}
+	first, _, _ := strings.Cut(msg, "\n")
+	return fmt.Sprintf("%d %s", code, first), err
+}
+
+// smtpCapabilities envia o EHLO; cada linha da resposta depois da primeira é uma extensão
+func smtpCapabilities(tp *textproto.Conn, hostname string) (map[string]bool, error) {
+	if err := tp.PrintfLine("EHLO %s", hostname); err != nil {
+		return nil, err
+	}
+	_, msg, err := tp.ReadResponse(250)
+	if err != nil {
+		return nil, err
+	}
+	caps := make(map[string]bool)
+	lines := strings.Split(msg, "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(strings.ToUpper(line))
+		if len(fields) == 0 {
+			continue
+		}
+		caps[fields[0]] = true
+		if fields[0] == "AUTH" {
+			for _, mech := range fields[1:] {
+				caps["AUTH="+mech] = true
+			}
+		}
+	}
+	return caps, nil
+}
+
+func smtpStartTLS(tp *textproto.Conn) error {
+	if err := tp.PrintfLine("STARTTLS"); err != nil {
+		return err
+	}
+	if _, _, err := tp.ReadResponse(220); err != nil {
+		return fmt.Errorf("smtp: STARTTLS recusado: %w", err)
+	}
+	return nil
+}
+
+// smtpLogin usa AUTH PLAIN e, quando o servidor não anuncia PLAIN, AUTH LOGIN
+func smtpLogin(tp *textproto.Conn, user, password string, caps map[string]bool) error {
+	encode := base64.StdEncoding.EncodeToString
+	switch {
+	case caps["AUTH=PLAIN"]:
+		if err := tp.PrintfLine("AUTH PLAIN %s", encode([]byte("\x00"+user+"\x00"+password))); err != nil {
+			return err
+		}
+	case caps["AUTH=LOGIN"]:
+		for _, step := range []string{"AUTH LOGIN", encode([]byte(user))} {
+			if err := tp.PrintfLine("%s", step); err != nil {
+				return err
+			}
+			if _, _, err := tp.ReadResponse(334); err != nil {
+				return err
+			}
+		}
+		if err := tp.PrintfLine("%s", encode([]byte(password))); err != nil {
+			return err
+		}
+	default:
+		return errors.New("o servidor não anuncia AUTH PLAIN nem AUTH LOGIN")
+	}
+	_, _, err := tp.ReadResponse(235)
+	return err
+}
+
+func imapGreet(tp *textproto.Conn) (string, error) {
+	line, err := tp.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "* OK") && !strings.HasPrefix(line, "* PREAUTH") {
+		return line, fmt.Errorf("saudação inesperada: %s", truncateBanner(line))
+	}
+	return line, nil
+}
+
+// imapCommand envia o comando com a tag e devolve as respostas não marcadas até a resposta da tag
+func imapCommand(tp *textproto.Conn, tag, command string) ([]string, error) {
+	if err := tp.PrintfLine("%s %s", tag, command); err != nil {
+		return nil, err
+	}
+	var untagged []string
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return untagged, err
+		}
+		if !strings.HasPrefix(line, tag+" ") {
+			untagged = append(untagged, line)
+			continue
+		}
+		if !strings.HasPrefix(line, tag+" OK") {
+			return untagged, fmt.Errorf("imap: %s", truncateBanner(line))
+		}
+		return untagged, nil
+	}
+}
+
+func imapCapabilities(tp *textproto.Conn, _ string) (map[string]bool, error) {
+	lines, err := imapCommand(tp, "a1", "CAPABILITY")
+	if err != nil {
+		return nil, err
+	}
+	caps := make(map[string]bool)
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(strings.ToUpper(line), "* CAPABILITY "); ok {
+			for _, c := range strings.Fields(rest) {
+				caps[c] = true
+			}
+		}
+	}
+	return caps, nil
+}
+
+func imapStartTLS(tp *textproto.Conn) error {
+	if _, err := imapCommand(tp, "a2", "STARTTLS"); err != nil {
+		return fmt.Errorf("STARTTLS recusado: %w", err)
+	}
+	return nil
+}
+
+func imapLogin(tp *textproto.Conn, user, password string, caps map[string]bool) error {
+	if caps["LOGINDISABLED"] {
+		return errors.New("o servidor anuncia LOGINDISABLED")
+	}
+	_, err := imapCommand(tp, "a3", "LOGIN "+imapQuote(user)+" "+imapQuote(password))
+	return err
+}
+
+// imapQuote monta uma quoted string do IMAP, escapando barra invertida e aspas
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func pop3Greet(tp *textproto.Conn) (string, error) {
+	line, err := tp.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return line, fmt.Errorf("saudação inesperada: %s", truncateBanner(line))
+	}
+	return line, nil
+}
+
+// pop3Capabilities usa o CAPA (RFC 2449); servidores sem CAPA respondem -ERR e ficam sem capabilities.
+// STLS também é registrado como STARTTLS e os mecanismos de SASL como AUTH=MECANISMO.
+func pop3Capabilities(tp *textproto.Conn, _ string) (map[string]bool, error) {
+	if err := tp.PrintfLine("CAPA"); err != nil {
+		return nil, err
+	}
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	caps := make(map[string]bool)
+	if !strings.HasPrefix(line, "+OK") {
+		return caps, nil
+	}
+	lines, err := tp.ReadDotLines()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		fields := strings.Fields(strings.ToUpper(line))
+		if len(fields) == 0 {
+			continue
+		}
+		caps[fields[0]] = true
+		switch fields[0] {
+		case "STLS":
+			caps["STARTTLS"] = true
+		case "SASL":
+			for _, mech := range fields[1:] {
+				caps["AUTH="+mech] = true
+			}
+		}
+	}
+	return caps, nil
+}
+
+func pop3StartTLS(tp *textproto.Conn) error {
+	if err := tp.PrintfLine("STLS"); err != nil {
+		return err
+	}
+	return expectPrefix(tp, "+OK", "pop3")
+}
+
+func pop3Login(tp *textproto.Conn, user, password string, _ map[string]bool) error {
+	if err := tp.PrintfLine("USER %s", user); err != nil {
+		return err
+	}
+	if err := expectPrefix(tp, "+OK", "pop3"); err != nil {
+		return err
+	}
+	if err := tp.PrintfLine("PASS %s", password); err != nil {
+		return err
+	}
+	return expectPrefix(tp, "+OK", "pop3")
+}
@@ -0,0 +1,262 @@
+package monitors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// DefaultRDAPBootstrapURL é o registro da IANA com os servidores RDAP de cada TLD (RFC 9224)
+	DefaultRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+	rdapTimeout      = 15 * time.Second
+	rdapBootstrapTTL = 24 * time.Hour
+	maxRDAPSize      = 1 << 20
+)
+
+// domainHoldStatus são os códigos EPP que indicam domínio suspenso ou prestes a ser removido
+var domainHoldStatus = map[string]bool{
+	"clientHold":       true,
+	"serverHold":       true,
+	"redemptionPeriod": true,
+	"pendingDelete":    true,
+}
+
+// RDAPClient consulta o registro de domínios via RDAP. Quando baseURL é informado ele é usado para
+// todos os TLDs (ex.: servidor local de testes); caso contrário o servidor vem do bootstrap.
+type RDAPClient struct {
+	bootstrapURL string
+	baseURL      string
+	client       *http.Client
+
+	mu       sync.Mutex
+	services map[string]string // tld -> URL base do servidor RDAP
+	loadedAt time.Time
+}
+
+func NewRDAPClient(bootstrapURL, baseURL string) *RDAPClient {
+	if bootstrapURL == "" {
+		bootstrapURL = DefaultRDAPBootstrapURL
+	}
+	return &RDAPClient{
+		bootstrapURL: bootstrapURL,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		client:       &http.Client{Timeout: rdapTimeout},
+	}
+}
+
+// rdapDomain é o subconjunto da resposta de domínio da RFC 9083 usado no relatório
+type rdapDomain struct {
+	Status []string `json:"status"`
+	Events []struct {
+		Action string    `json:"eventAction"`
+		Date   time.Time `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string          `json:"roles"`
+		VCardArray []json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// FetchDomain consulta o RDAP do domínio registrável de domain e monta o relatório de expiração
+func (c *RDAPClient) FetchDomain(ctx context.Context, domain string, cfg *entities.DomainCheck) (*entities.DomainData, error) {
+	name, err := RegistrableDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := c.serverFor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp rdapDomain
+	if err := c.getJSON(ctx, base+"/domain/"+name, &resp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &entities.DomainData{Domain: name, CheckedAt: now.UTC()}
+	for _, ev := range resp.Events {
+		if ev.Action == "expiration" {
+			report.ExpirationDate = ev.Date
+		}
+	}
+	for _, ent := range resp.Entities {
+		if slices.Contains(ent.Roles, "registrar") {
+			report.Registrar = vcardName(ent.VCardArray)
+		}
+	}
+	for _, s := range resp.Status {
+		code := eppStatus(s)
+		report.StatusCodes = append(report.StatusCodes, code)
+		if domainHoldStatus[code] {
+			report.Problems = append(report.Problems, "domínio com status "+code)
+		}
+	}
+
+	if report.ExpirationDate.IsZero() {
+		report.Problems = append(report.Problems, "o RDAP não informa a data de expiração")
+		return report, nil
+	}
+	report.Expired = now.After(report.ExpirationDate)
+	report.DaysLeft = entities.DaysUntil(report.ExpirationDate, now)
+	switch {
+	case report.Expired:
+		report.Problems = append(report.Problems, fmt.Sprintf("domínio expirado em %s", report.ExpirationDate.Format("02/01/2006")))
+	case cfg.CrossedThreshold(report.DaysLeft) > 0:
+		report.Problems = append(report.Problems, fmt.Sprintf("domínio expira em %d dia(s)", report.DaysLeft))
+	}
+	return report, nil
+}
+
+func validateDomainCheck(e *entities.Endpoint) error {
+	if !e.CheckDomain {
+		return nil
+	}
+	if _, err := RegistrableDomain(e.Domain); err != nil {
+		return fmt.Errorf("check_domain: %w", err)
+	}
+	if e.DomainCheck == nil {
+		return nil
+	}
+	if e.DomainCheck.Interval < 0 {
+		return errors.New("domain_check.interval não pode ser negativo")
+	}
+	for _, d := range e.DomainCheck.AlertDays {
+		if d <= 0 {
+			return errors.New("domain_check.alert_days deve conter apenas valores positivos")
+		}
+	}
+	return nil
+}
+
+// RegistrableDomain devolve o domínio registrável (eTLD+1) do host, ex.: api.example.com.br -> example.com.br
+func RegistrableDomain(domain string) (string, error) {
+	host := strings.TrimSuffix(strings.ToLower(hostOnly(domain)), ".")
+	if host == "" || net.ParseIP(host) != nil {
+		return "", fmt.Errorf("%q não é um nome de domínio", domain)
+	}
+	return publicsuffix.EffectiveTLDPlusOne(host)
+}
+
+// serverFor devolve a URL base do servidor RDAP responsável pelo TLD do domínio
+func (c *RDAPClient) serverFor(ctx context.Context, name string) (string, error) {
+	if c.baseURL != "" {
+		return c.baseURL, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.services == nil || time.Since(c.loadedAt) > rdapBootstrapTTL {
+		if err := c.loadBootstrap(ctx); err != nil {
+			return "", fmt.Errorf("bootstrap RDAP: %w", err)
+		}
+	}
+
+	// o TLD mais específico vence (ex.: com.br antes de br)
+	labels := strings.Split(name, ".")
+	for i := 1; i < len(labels); i++ {
+		if base, ok := c.services[strings.Join(labels[i:], ".")]; ok {
+			return base, nil
+		}
+	}
+	return "", fmt.Errorf("nenhum servidor RDAP conhecido para %s", name)
+}
+
+// loadBootstrap carrega o arquivo de bootstrap: {"services": [[["com", "net"], ["https://rdap.exemplo/"]], ...]}
+func (c *RDAPClient) loadBootstrap(ctx context.Context) error {
+	var bootstrap struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := c.getJSON(ctx, c.bootstrapURL, &bootstrap); err != nil {
+		return err
+	}
+
+	services := make(map[string]string)
+	for _, svc := range bootstrap.Services {
+		if len(svc) < 2 || len(svc[1]) == 0 {
+			continue
+		}
+		base := svc[1][0]
+		for _, u := range svc[1] {
+			if strings.HasPrefix(u, "https://") {
+				base = u
+				break
+			}
+		}
+		for _, tld := range svc[0] {
+			services[strings.ToLower(tld)] = strings.TrimRight(base, "/")
+		}
+	}
+	if len(services) == 0 {
+		return errors.New("arquivo de bootstrap sem serviços")
+	}
+	c.services = services
+	c.loadedAt = time.Now()
+	return nil
+}
+
+func (c *RDAPClient) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+	req.Header.Set("User-Agent", "Ratatoskr/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: domínio não encontrado no RDAP", url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s respondeu HTTP %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxRDAPSize)).Decode(v)
+}
+
+// eppStatus converte o status RDAP ("client hold") para o código EPP ("clientHold")
+func eppStatus(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// vcardName extrai o campo fn de um jCard: ["vcard", [["fn", {}, "text", "Nome"], ...]]
+func vcardName(vcard []json.RawMessage) string {
+	if len(vcard) < 2 {
+		return ""
+	}
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(vcard[1], &props); err != nil {
+		return ""
+	}
+	for _, p := range props {
+		var key, value string
+		if len(p) < 4 || json.Unmarshal(p[0], &key) != nil || key != "fn" {
+			continue
+		}
+		if json.Unmarshal(p[3], &value) == nil {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package monitors
+
+import "testing"
+
+func TestEPPStatus(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"active", "active"},
+		{"client hold", "clientHold"},
+		{"client transfer prohibited", "clientTransferProhibited"},
+		{"Redemption Period", "redemptionPeriod"},
+		{"  pending   delete ", "pendingDelete"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := eppStatus(tt.in); got != tt.want {
+			t.Errorf("eppStatus(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "example.com", want: "example.com"},
+		{in: "api.example.com", want: "example.com"},
+		{in: "API.Example.COM.", want: "example.com"},
+		{in: "https://api.example.com.br:8443/health", want: "example.com.br"},
+		{in: "www.example.co.uk", want: "example.co.uk"},
+		{in: "192.0.2.10", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := RegistrableDomain(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("RegistrableDomain(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RegistrableDomain(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
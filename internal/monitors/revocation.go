@@ -0,0 +1,228 @@
+package monitors
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// maxRevocationSize limita o download de respostas OCSP e CRLs
+	maxRevocationSize = 10 << 20
+	// revocationFallbackTTL é usado quando a resposta não informa nextUpdate
+	revocationFallbackTTL = time.Hour
+)
+
+type cachedOCSP struct {
+	response *ocsp.Response
+	expires  time.Time
+}
+
+type cachedCRL struct {
+	list    *x509.RevocationList
+	expires time.Time
+}
+
+var (
+	revocationMu sync.Mutex
+	// ocspCache guarda respostas OCSP por responder + serial até o nextUpdate
+	ocspCache = make(map[string]cachedOCSP)
+	// crlCache guarda CRLs por URL até o nextUpdate
+	crlCache = make(map[string]cachedCRL)
+
+	revocationClient = &http.Client{Timeout: sslDialTimeout}
+)
+
+// checkRevocation consulta o status de revogação do leaf: OCSP grampeado, OCSP do responder e, se
+// necessário, as CRLs. Sem o certificado emissor não é possível validar as respostas.
+func checkRevocation(ctx context.Context, stapled []byte, leaf, issuer *x509.Certificate, cfg *entities.SSLCheck) *entities.RevocationStatus {
+	now := time.Now()
+	result := &entities.RevocationStatus{Status: entities.RevocationUnknown, CheckedAt: now.UTC()}
+	if issuer == nil {
+		result.Error = "certificado emissor não enviado pelo servidor"
+		return result
+	}
+
+	var errs []error
+
+	if len(stapled) > 0 {
+		resp, err := ocsp.ParseResponseForCert(stapled, leaf, issuer)
+		if err == nil {
+			return fromOCSP(result, resp, "ocsp-stapled")
+		}
+		errs = append(errs, fmt.Errorf("ocsp grampeado: %w", err))
+	}
+
+	responder := ""
+	if cfg != nil && cfg.OCSPResponder != "" {
+		responder = cfg.OCSPResponder
+	} else if len(leaf.OCSPServer) > 0 {
+		responder = leaf.OCSPServer[0]
+	}
+	if responder != "" {
+		resp, err := fetchOCSP(ctx, responder, leaf, issuer, now)
+		if err == nil && resp.Status != ocsp.Unknown {
+			return fromOCSP(result, resp, "ocsp")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ocsp: %w", err))
+		}
+	}
+
+	for _, url := range leaf.CRLDistributionPoints {
+		list, err := fetchCRL(ctx, url, issuer, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("crl: %w", err))
+			continue
+		}
+		result.Source = "crl"
+		result.Status = entities.RevocationGood
+		for _, entry := range list.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				result.Status = entities.RevocationRevoked
+				result.RevokedAt = entry.RevocationTime
+				result.Reason = revocationReason(entry.ReasonCode)
+				break
+			}
+		}
+		return result
+	}
+
+	if len(errs) > 0 {
+		result.Error = errors.Join(errs...).Error()
+	} else if responder == "" {
+		result.Error = "o certificado não informa OCSP nem CRL"
+	}
+	return result
+}
+
+func fromOCSP(result *entities.RevocationStatus, resp *ocsp.Response, source string) *entities.RevocationStatus {
+	result.Source = source
+	switch resp.Status {
+	case ocsp.Good:
+		result.Status = entities.RevocationGood
+	case ocsp.Revoked:
+		result.Status = entities.RevocationRevoked
+		result.RevokedAt = resp.RevokedAt
+		result.Reason = revocationReason(resp.RevocationReason)
+	}
+	return result
+}
+
+func fetchOCSP(ctx context.Context, responder string, leaf, issuer *x509.Certificate, now time.Time) (*ocsp.Response, error) {
+	key := responder + "|" + leaf.SerialNumber.String()
+	revocationMu.Lock()
+	cached, ok := ocspCache[key]
+	revocationMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.response, nil
+	}
+
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := download(ctx, http.MethodPost, responder, "application/ocsp-request", req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	revocationMu.Lock()
+	ocspCache[key] = cachedOCSP{response: resp, expires: cacheUntil(resp.NextUpdate, now)}
+	revocationMu.Unlock()
+	return resp, nil
+}
+
+func fetchCRL(ctx context.Context, url string, issuer *x509.Certificate, now time.Time) (*x509.RevocationList, error) {
+	revocationMu.Lock()
+	cached, ok := crlCache[url]
+	revocationMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.list, nil
+	}
+
+	body, err := download(ctx, http.MethodGet, url, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	list, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := list.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("assinatura da CRL inválida: %w", err)
+	}
+
+	revocationMu.Lock()
+	crlCache[url] = cachedCRL{list: list, expires: cacheUntil(list.NextUpdate, now)}
+	revocationMu.Unlock()
+	return list, nil
+}
+
+func download(ctx context.Context, method, url, contentType string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("User-Agent", "Ratatoskr/1.0")
+
+	resp, err := revocationClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s respondeu HTTP %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxRevocationSize))
+}
+
+// findIssuer procura, entre os certificados enviados, o que assinou o leaf
+func findIssuer(leaf *x509.Certificate, chain []*x509.Certificate) *x509.Certificate {
+	for _, c := range chain {
+		if bytes.Equal(c.RawSubject, leaf.RawIssuer) && leaf.CheckSignatureFrom(c) == nil {
+			return c
+		}
+	}
+	return nil
+}
+
+func cacheUntil(nextUpdate, now time.Time) time.Time {
+	if nextUpdate.IsZero() || nextUpdate.Before(now) {
+		return now.Add(revocationFallbackTTL)
+	}
+	return nextUpdate
+}
+
+// revocationReason traduz o código de motivo da RFC 5280
+func revocationReason(code int) string {
+	reasons := map[int]string{
+		ocsp.Unspecified:          "unspecified",
+		ocsp.KeyCompromise:        "keyCompromise",
+		ocsp.CACompromise:         "cACompromise",
+		ocsp.AffiliationChanged:   "affiliationChanged",
+		ocsp.Superseded:           "superseded",
+		ocsp.CessationOfOperation: "cessationOfOperation",
+		ocsp.CertificateHold:      "certificateHold",
+		ocsp.RemoveFromCRL:        "removeFromCRL",
+		ocsp.PrivilegeWithdrawn:   "privilegeWithdrawn",
+		ocsp.AACompromise:         "aACompromise",
+	}
+	return reasons[code]
+}
@@ -0,0 +1,242 @@
+package monitors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/tidwall/gjson"
+)
+
+// scenarioVar encontra as variáveis {{ nome }} nos passos do cenário
+var scenarioVar = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+func validateScenario(e *entities.Endpoint) error {
+	if e.Scenario == nil || len(e.Scenario.Steps) == 0 {
+		return errors.New("scenario.steps deve ter ao menos um passo")
+	}
+	if err := validateAuth(e.Authentication); err != nil {
+		return err
+	}
+	for i, step := range e.Scenario.Steps {
+		if step.URL == "" {
+			return fmt.Errorf("scenario.steps[%d].url é obrigatório", i)
+		}
+		if step.Method != "" && !validMethod(step.Method) {
+			return fmt.Errorf("scenario.steps[%d].method inválido: %s", i, step.Method)
+		}
+		for name, path := range step.Extract {
+			if name == "" || path == "" || strings.TrimPrefix(path, "header:") == "" {
+				return fmt.Errorf("scenario.steps[%d].extract inválido: %s", i, name)
+			}
+		}
+		if err := validateAssertions(step.Assertions); err != nil {
+			return fmt.Errorf("scenario.steps[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// checkScenario executa os passos em ordem com um cookie jar compartilhado; o primeiro passo com falha encerra o check
+func checkScenario(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	if e.Scenario == nil {
+		result.ErrorMessage = "configuração scenario ausente"
+		return result
+	}
+
+	base, err := httpClient(e.Authentication)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	jar, _ := cookiejar.New(nil)
+	client := *base
+	client.Jar = jar
+
+	vars := make(map[string]string)
+	start := time.Now()
+	for i, step := range e.Scenario.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("passo %d", i+1)
+		}
+
+		stepResult, err := runStep(ctx, &client, e, step, vars)
+		stepResult.Name = name
+		result.Metrics[fmt.Sprintf("step_%d_ms", i+1)] = milliseconds(stepResult.ResponseTime)
+		if err != nil {
+			stepResult.ErrorMessage = err.Error()
+			result.Steps = append(result.Steps, stepResult)
+			result.StatusCode = stepResult.StatusCode
+			result.ResponseTime = time.Since(start)
+			result.ErrorMessage = fmt.Sprintf("%s: %v", name, err)
+			return result
+		}
+		stepResult.Passed = true
+		result.Steps = append(result.Steps, stepResult)
+		result.StatusCode = stepResult.StatusCode
+	}
+
+	result.ResponseTime = time.Since(start)
+	result.Status = entities.StatusOnline
+	return result
+}
+
+func runStep(ctx context.Context, client *http.Client, e *entities.Endpoint, step entities.ScenarioStep, vars map[string]string) (entities.StepResult, error) {
+	var stepResult entities.StepResult
+
+	rawURL, err := substitute(step.URL, vars)
+	if err != nil {
+		return stepResult, err
+	}
+	target, err := resolveStepURL(e.URL(), rawURL)
+	if err != nil {
+		return stepResult, err
+	}
+	body, err := substitute(step.Body, vars)
+	if err != nil {
+		return stepResult, err
+	}
+
+	method := strings.ToUpper(step.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	if err != nil {
+		return stepResult, err
+	}
+	req.Header.Set("User-Agent", "Ratatoskr/1.0")
+	for k, v := range step.Headers {
+		value, err := substitute(v, vars)
+		if err != nil {
+			return stepResult, err
+		}
+		req.Header.Set(k, value)
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" && gjson.Valid(body) {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// a autenticação do endpoint só vai para o próprio host: URLs absolutas (ou montadas com variáveis
+	// extraídas) podem apontar para terceiros
+	if sameHost(e.URL(), req.URL) {
+		if err := applyAuth(req, e.Authentication); err != nil {
+			return stepResult, err
+		}
+	}
+
+	req, tracer := withTrace(req)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		stepResult.ResponseTime = time.Since(start)
+		stepResult.Timings = tracer.timings(time.Now())
+		return stepResult, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	rest, _ := io.Copy(io.Discard, resp.Body)
+	stepResult.ResponseTime = time.Since(start)
+	stepResult.Timings = tracer.timings(time.Now())
+	stepResult.StatusCode = resp.StatusCode
+
+	if step.ExpectStatus > 0 && resp.StatusCode != step.ExpectStatus {
+		return stepResult, fmt.Errorf("status HTTP %d (esperado %d)", resp.StatusCode, step.ExpectStatus)
+	}
+	if step.ExpectStatus == 0 && resp.StatusCode >= http.StatusBadRequest {
+		return stepResult, fmt.Errorf("status HTTP inesperado: %d", resp.StatusCode)
+	}
+
+	assertions := evaluateAssertions(step.Assertions, resp, respBody, int64(len(respBody))+rest, stepResult.ResponseTime)
+	if failures := assertionFailures(assertions); len(failures) > 0 {
+		return stepResult, errors.New(strings.Join(failures, "; "))
+	}
+
+	for name, path := range step.Extract {
+		if header, ok := strings.CutPrefix(path, "header:"); ok {
+			value := resp.Header.Get(header)
+			if value == "" {
+				return stepResult, fmt.Errorf("header %s ausente para extrair %s", header, name)
+			}
+			vars[name] = value
+			continue
+		}
+		value := gjson.GetBytes(respBody, path)
+		if !value.Exists() {
+			return stepResult, fmt.Errorf("caminho %s ausente na resposta para extrair %s", path, name)
+		}
+		vars[name] = value.String()
+	}
+	return stepResult, nil
+}
+
+// substitute troca {{ nome }} pelo valor extraído; variável desconhecida é erro para não enviar o placeholder
+func substitute(s string, vars map[string]string) (string, error) {
+	var missing []string
+	out := scenarioVar.ReplaceAllStringFunc(s, func(m string) string {
+		name := scenarioVar.FindStringSubmatch(m)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("variável não definida: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+func resolveStepURL(base, ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if u.IsAbs() {
+		return ref, nil
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	// paths relativos partem da raiz do Domain, não do path de health check
+	b.Path, b.RawQuery = "", ""
+	return b.ResolveReference(u).String(), nil
+}
+
+// sameHost compara host e porta (efetiva) de target com os da URL base
+func sameHost(base string, target *url.URL) bool {
+	b, err := url.Parse(base)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(b.Hostname(), target.Hostname()) && effectivePort(b) == effectivePort(target)
+}
+
+func effectivePort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return "443"
+	}
+	return "80"
+}
+
+func validMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package monitors
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSameHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   bool
+	}{
+		{"path relativo resolvido", "https://api.example.com/login", true},
+		{"host em maiúsculas", "https://API.example.com/login", true},
+		{"porta padrão explícita", "https://api.example.com:443/login", true},
+		{"outro host", "https://evil.example.net/login", false},
+		{"subdomínio diferente", "https://auth.example.com/token", false},
+		{"outra porta", "https://api.example.com:8443/login", false},
+		{"http na porta 443 implícita", "http://api.example.com/login", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := url.Parse(tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sameHost("https://api.example.com/health", target); got != tt.want {
+				t.Errorf("sameHost(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
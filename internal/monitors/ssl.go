@@ -1,41 +1,300 @@
 package monitors
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
 )
 
-func FetchSSL(domain string, port int) (time.Time, error) {
-	host := domain
-	if _, _, err := net.SplitHostPort(domain); err != nil {
-		host = net.JoinHostPort(domain, strconv.Itoa(port))
+const sslDialTimeout = 10 * time.Second
+
+// SSLPort devolve a porta usada no check de certificado: ssl.port, a porta do endpoint (http/tcp/grpc/websocket),
+// a porta padrão do protocolo starttls ou 443
+func SSLPort(e *entities.Endpoint) int {
+	if e.SSL != nil && e.SSL.Port > 0 {
+		return e.SSL.Port
+	}
+	if e.Port > 0 && (e.MonitorType() == entities.TypeHTTP || e.MonitorType() == entities.TypeTCP || e.MonitorType() == entities.TypeGRPC || e.MonitorType() == entities.TypeWebSocket) {
+		return e.Port
+	}
+	if e.SSL != nil && e.SSL.StartTLS != "" {
+		return startTLSPorts[e.SSL.StartTLS]
+	}
+	return 443
+}
+
+// FetchSSL conecta em domain:port (negociando STARTTLS quando configurado) e monta o relatório TLS:
+// cadeia, hostname, chave, versão e cifra. O handshake não valida o certificado para que o relatório
+// seja gerado mesmo quando ele é inválido.
+func FetchSSL(ctx context.Context, domain string, port int, cfg *entities.SSLCheck) (*entities.SSLData, error) {
+	host := hostOnly(domain)
+	serverName := host
+	if cfg != nil && cfg.ServerName != "" {
+		serverName = cfg.ServerName
+	}
+
+	dialer := &net.Dialer{Timeout: sslDialTimeout}
+	raw, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Close()
+
+	deadline := time.Now().Add(sslDialTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
 	}
+	_ = raw.SetDeadline(deadline)
 
-	dialer := &net.Dialer{
-		Timeout: 10 * time.Second,
+	if cfg != nil && cfg.StartTLS != "" {
+		if err := startTLS(raw, cfg.StartTLS, serverName); err != nil {
+			return nil, err
+		}
 	}
 
-	conn, err := tls.DialWithDialer(dialer, "tcp", host, &tls.Config{
-		InsecureSkipVerify: true,
+	conn := tls.Client(raw, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true, // a verificação é feita abaixo, com o resultado detalhado
 	})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, err
+	}
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("nenhum certificado encontrado")
+	}
+
+	roots, err := rootPool(cfg)
 	if err != nil {
-		return time.Time{}, err
+		return nil, err
 	}
-	defer func() {
-		if err := conn.Close(); err != nil {
-			// Log the error but don't override the main function's return error
-			// since connection close errors are typically not critical
-			fmt.Printf("Warning: failed to close SSL connection: %v\n", err)
+	report := buildSSLReport(state, serverName, roots, cfg, time.Now())
+
+	if cfg == nil || !cfg.SkipRevocation {
+		leaf := state.PeerCertificates[0]
+		issuer := findIssuer(leaf, state.PeerCertificates[1:])
+		report.Revocation = checkRevocation(ctx, state.OCSPResponse, leaf, issuer, cfg)
+		if report.Revocation.Status == entities.RevocationRevoked {
+			report.Status = entities.SSLRevoked
+			problem := fmt.Sprintf("certificado revogado em %s", report.Revocation.RevokedAt.Format("02/01/2006"))
+			if report.Revocation.Reason != "" {
+				problem += " (" + report.Revocation.Reason + ")"
+			}
+			report.Problems = append([]string{problem}, report.Problems...)
 		}
-	}()
+	}
+	return report, nil
+}
 
-	certs := conn.ConnectionState().PeerCertificates
-	if len(certs) == 0 {
-		return time.Time{}, fmt.Errorf("nenhum certificado encontrado")
+func buildSSLReport(state tls.ConnectionState, host string, roots *x509.CertPool, cfg *entities.SSLCheck, now time.Time) *entities.SSLData {
+	certs := state.PeerCertificates
+	leaf := certs[0]
+
+	report := &entities.SSLData{
+		ExpirationDate:     leaf.NotAfter,
+		Expired:            now.After(leaf.NotAfter),
+		DaysLeft:           entities.DaysUntil(leaf.NotAfter, now),
+		Issuer:             leaf.Issuer.CommonName,
+		Subject:            leaf.Subject.CommonName,
+		SANs:               certificateSANs(leaf),
+		SerialNumber:       leaf.SerialNumber.Text(16),
+		NotBefore:          leaf.NotBefore,
+		SignatureAlgorithm: leaf.SignatureAlgorithm.String(),
+		TLSVersion:         tls.VersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		ChainComplete:      true,
+		CheckedAt:          now.UTC(),
+	}
+	if report.Issuer == "" {
+		report.Issuer = leaf.Issuer.String()
+	}
+	if report.Subject == "" {
+		report.Subject = leaf.Subject.String()
+	}
+	report.KeyType, report.KeySize = publicKeyInfo(leaf)
+	report.Fingerprint = sha256Hex(leaf.Raw)
+	report.SPKIFingerprint = sha256Hex(leaf.RawSubjectPublicKeyInfo)
+
+	var invalid, expiring []string
+
+	if cfg != nil && len(cfg.Pins) > 0 {
+		matched := pinMatches(cfg.Pins, certs)
+		report.PinMatched = &matched
+		if !matched {
+			invalid = append(invalid, "o certificado não corresponde a nenhum pin configurado")
+		}
 	}
 
-	return certs[0].NotAfter, nil
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+		report.Chain = append(report.Chain, entities.CertificateInfo{
+			Subject:  c.Subject.CommonName,
+			Issuer:   c.Issuer.CommonName,
+			NotAfter: c.NotAfter,
+			DaysLeft: entities.DaysUntil(c.NotAfter, now),
+		})
+	}
+
+	_, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: now})
+	report.ChainVerified = err == nil
+	if err != nil {
+		var unknown x509.UnknownAuthorityError
+		if errors.As(err, &unknown) && !issuerSent(leaf, certs[1:]) && !bytes.Equal(leaf.RawIssuer, leaf.RawSubject) {
+			report.ChainComplete = false
+			invalid = append(invalid, "cadeia incompleta: o servidor não envia o certificado intermediário")
+		} else {
+			invalid = append(invalid, "cadeia não confiável: "+err.Error())
+		}
+	}
+
+	report.HostnameMatch = leaf.VerifyHostname(host) == nil
+	if !report.HostnameMatch {
+		invalid = append(invalid, fmt.Sprintf("o certificado não é válido para %s", host))
+	}
+
+	switch {
+	case report.Expired:
+		invalid = append(invalid, fmt.Sprintf("certificado expirado em %s", leaf.NotAfter.Format("02/01/2006")))
+	case cfg.InvalidThreshold() > 0 && report.DaysLeft <= cfg.InvalidThreshold():
+		invalid = append(invalid, fmt.Sprintf("certificado expira em %d dia(s)", report.DaysLeft))
+	case report.DaysLeft <= cfg.ExpiringThreshold():
+		expiring = append(expiring, fmt.Sprintf("certificado expira em %d dia(s)", report.DaysLeft))
+	}
+
+	for _, c := range report.Chain {
+		if c.DaysLeft <= cfg.ExpiringThreshold() {
+			expiring = append(expiring, fmt.Sprintf("intermediário %s expira em %d dia(s)", c.Subject, c.DaysLeft))
+		}
+	}
+
+	report.Problems = append(invalid, expiring...)
+	switch {
+	case len(invalid) > 0:
+		report.Status = entities.SSLInvalid
+	case len(expiring) > 0:
+		report.Status = entities.SSLExpiring
+	default:
+		report.Status = entities.SSLValid
+	}
+	return report
+}
+
+func validateSSL(cfg *entities.SSLCheck) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return errors.New("ssl.port inválido")
+	}
+	if cfg.ExpiringDays < 0 || cfg.InvalidDays < 0 {
+		return errors.New("ssl.expiring_days e ssl.invalid_days não podem ser negativos")
+	}
+	if cfg.ExpiringDays > 0 && cfg.InvalidDays >= cfg.ExpiringDays {
+		return errors.New("ssl.invalid_days deve ser menor que ssl.expiring_days")
+	}
+	if _, ok := startTLSPorts[cfg.StartTLS]; cfg.StartTLS != "" && !ok {
+		return fmt.Errorf("ssl.starttls inválido: %s", cfg.StartTLS)
+	}
+	if cfg.OCSPResponder != "" {
+		if u, err := url.Parse(cfg.OCSPResponder); err != nil || u.Host == "" {
+			return errors.New("ssl.ocsp_responder inválido")
+		}
+	}
+	if cfg.Interval < 0 {
+		return errors.New("ssl.interval não pode ser negativo")
+	}
+	for _, d := range cfg.AlertDays {
+		if d <= 0 {
+			return errors.New("ssl.alert_days deve conter apenas valores positivos")
+		}
+	}
+	for _, pin := range cfg.Pins {
+		if b, err := hex.DecodeString(normalizeFingerprint(pin)); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("ssl.pins: %q não é um SHA-256 em hexadecimal", pin)
+		}
+	}
+	_, err := rootPool(cfg)
+	return err
+}
+
+// rootPool usa as raízes configuradas no endpoint ou as do sistema
+func rootPool(cfg *entities.SSLCheck) (*x509.CertPool, error) {
+	if cfg == nil || cfg.RootCAs == "" {
+		return x509.SystemCertPool()
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(cfg.RootCAs)) {
+		return nil, errors.New("ssl.root_cas inválido")
+	}
+	return pool, nil
+}
+
+// issuerSent indica se o servidor enviou o certificado que emitiu o leaf
+func issuerSent(leaf *x509.Certificate, chain []*x509.Certificate) bool {
+	for _, c := range chain {
+		if bytes.Equal(c.RawSubject, leaf.RawIssuer) {
+			return true
+		}
+	}
+	return false
+}
+
+func certificateSANs(c *x509.Certificate) []string {
+	sans := append([]string{}, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+func publicKeyInfo(c *x509.Certificate) (string, int) {
+	switch key := c.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return c.PublicKeyAlgorithm.String(), 0
+}
+
+// pinMatches indica se algum certificado da cadeia enviada corresponde a um dos pins (certificado ou SPKI)
+func pinMatches(pins []string, certs []*x509.Certificate) bool {
+	for _, pin := range pins {
+		pin = normalizeFingerprint(pin)
+		for _, c := range certs {
+			if pin == sha256Hex(c.Raw) || pin == sha256Hex(c.RawSubjectPublicKeyInfo) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeFingerprint aceita o formato com ou sem ":" (ex.: saída do openssl)
+func normalizeFingerprint(s string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), ":", ""))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,416 @@
+package monitors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/miekg/dns"
+	starjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+const (
+	defaultStarlarkSteps = 10_000_000
+	maxStarlarkSteps     = 100_000_000
+	// maxScriptCalls limita as chamadas de rede (http, dns e tcp) por execução
+	maxScriptCalls = 20
+	// maxScriptLogs limita as linhas de print() guardadas por execução
+	maxScriptLogs = 200
+	// maxScriptRedirects limita os redirects seguidos por http.get/http.post
+	maxScriptRedirects = 5
+)
+
+// scriptHTTPClient é o cliente dos scripts: segue no máximo maxScriptRedirects redirects
+var scriptHTTPClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxScriptRedirects {
+			return fmt.Errorf("limite de %d redirects atingido", maxScriptRedirects)
+		}
+		return nil
+	},
+}
+
+// starlarkFileOptions permite while e set; recursão continua proibida
+var starlarkFileOptions = &syntax.FileOptions{Set: true, While: true}
+
+// starlarkModules são os nomes pré-declarados disponíveis para os scripts
+var starlarkModules = map[string]bool{"http": true, "dns": true, "tcp": true, "json": true}
+
+func validateStarlark(e *entities.Endpoint) error {
+	if e.Starlark == nil || strings.TrimSpace(e.Starlark.Script) == "" {
+		return errors.New("starlark.script é obrigatório para monitores starlark")
+	}
+	if e.Starlark.MaxSteps > maxStarlarkSteps {
+		return fmt.Errorf("starlark.max_steps não pode passar de %d", maxStarlarkSteps)
+	}
+	_, err := compileStarlark(e.Starlark.Script)
+	return err
+}
+
+func compileStarlark(src string) (*starlark.Program, error) {
+	_, prog, err := starlark.SourceProgramOptions(starlarkFileOptions, "check.star", src, func(name string) bool {
+		return starlarkModules[name]
+	})
+	if err != nil {
+		return nil, fmt.Errorf("starlark.script inválido: %w", err)
+	}
+	return prog, nil
+}
+
+// DryRunStarlark executa o script do endpoint e devolve também os logs (print) da execução
+func DryRunStarlark(ctx context.Context, e *entities.Endpoint) (CheckResult, []string) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout(e))
+	defer cancel()
+	return runStarlark(ctx, e)
+}
+
+// scriptRun guarda o estado de uma execução: contexto, logs e chamadas de rede já feitas
+type scriptRun struct {
+	ctx   context.Context
+	logs  []string
+	calls int
+}
+
+func (r *scriptRun) log(msg string) {
+	if len(r.logs) < maxScriptLogs {
+		r.logs = append(r.logs, msg)
+	}
+}
+
+func (r *scriptRun) call(name string) error {
+	r.calls++
+	if r.calls > maxScriptCalls {
+		return fmt.Errorf("%s: limite de %d chamadas de rede por execução", name, maxScriptCalls)
+	}
+	return nil
+}
+
+// runStarlark executa check() com limite de passos (CPU) e o Timeout do endpoint
+func runStarlark(ctx context.Context, e *entities.Endpoint) (CheckResult, []string) {
+	result := newResult()
+	result.Status = entities.StatusUnknown
+	if err := validateStarlark(e); err != nil {
+		result.ErrorMessage = err.Error()
+		return result, nil
+	}
+	prog, _ := compileStarlark(e.Starlark.Script)
+
+	run := &scriptRun{ctx: ctx}
+	thread := &starlark.Thread{
+		Name:  e.Name,
+		Print: func(_ *starlark.Thread, msg string) { run.log(msg) },
+		Load: func(_ *starlark.Thread, module string) (starlark.StringDict, error) {
+			return nil, errors.New("load não é permitido")
+		},
+	}
+	steps := e.Starlark.MaxSteps
+	if steps == 0 {
+		steps = defaultStarlarkSteps
+	}
+	thread.SetMaxExecutionSteps(steps)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel("timeout")
+		case <-done:
+		}
+	}()
+
+	start := time.Now()
+	value, err := execStarlark(thread, prog, run)
+	result.ResponseTime = time.Since(start)
+	result.Metrics["script_ms"] = milliseconds(result.ResponseTime)
+	result.Metrics["steps"] = float64(thread.ExecutionSteps())
+
+	if err != nil {
+		if ctx.Err() != nil {
+			result.Status = entities.StatusOffline
+			result.ErrorMessage = fmt.Sprintf("timeout após %s", Timeout(e))
+		} else {
+			result.ErrorMessage = "erro no script: " + err.Error()
+		}
+		run.log(result.ErrorMessage)
+		return result, run.logs
+	}
+
+	if err := applyScriptResult(&result, value); err != nil {
+		result.Status = entities.StatusUnknown
+		result.ErrorMessage = err.Error()
+		run.log(result.ErrorMessage)
+	}
+	return result, run.logs
+}
+
+func execStarlark(thread *starlark.Thread, prog *starlark.Program, run *scriptRun) (starlark.Value, error) {
+	globals, err := prog.Init(thread, scriptModules(run))
+	if err != nil {
+		return nil, formatStarlarkError(err)
+	}
+	check, ok := globals["check"].(*starlark.Function)
+	if !ok {
+		return nil, errors.New("o script deve definir a função check()")
+	}
+	value, err := starlark.Call(thread, check, nil, nil)
+	if err != nil {
+		return nil, formatStarlarkError(err)
+	}
+	return value, nil
+}
+
+// formatStarlarkError inclui o backtrace do script quando disponível
+func formatStarlarkError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+	return err
+}
+
+// applyScriptResult interpreta o retorno de check(): uma string com o status ou um dict com status, message e metrics
+func applyScriptResult(result *CheckResult, value starlark.Value) error {
+	var status, message string
+	switch v := value.(type) {
+	case starlark.String:
+		status = string(v)
+	case *starlark.Dict:
+		if s, ok, _ := v.Get(starlark.String("status")); ok {
+			str, isStr := starlark.AsString(s)
+			if !isStr {
+				return errors.New("check(): status deve ser uma string")
+			}
+			status = str
+		}
+		if m, ok, _ := v.Get(starlark.String("message")); ok {
+			if str, isStr := starlark.AsString(m); isStr {
+				message = str
+			} else {
+				message = m.String()
+			}
+		}
+		if m, ok, _ := v.Get(starlark.String("metrics")); ok {
+			metrics, isDict := m.(*starlark.Dict)
+			if !isDict {
+				return errors.New("check(): metrics deve ser um dict")
+			}
+			for _, item := range metrics.Items() {
+				name, _ := starlark.AsString(item[0])
+				n, isNum := starlark.AsFloat(item[1])
+				if name == "" || !isNum {
+					return fmt.Errorf("check(): métrica inválida %s=%s", item[0], item[1])
+				}
+				result.Metrics[metricLabel.Replace(name)] = n
+			}
+		}
+	default:
+		return fmt.Errorf("check() deve devolver uma string ou um dict, não %s", value.Type())
+	}
+
+	switch entities.EndpointStatus(status) {
+	case entities.StatusOnline, entities.StatusDegraded, entities.StatusOffline, entities.StatusUnknown:
+		result.Status = entities.EndpointStatus(status)
+	default:
+		return fmt.Errorf("check(): status inválido %q", status)
+	}
+	result.ErrorMessage = truncateBanner(message)
+	return nil
+}
+
+// scriptModules monta os módulos restritos expostos ao script
+func scriptModules(run *scriptRun) starlark.StringDict {
+	return starlark.StringDict{
+		"json": starjson.Module,
+		"http": &starlarkstruct.Module{Name: "http", Members: starlark.StringDict{
+			"get":     starlark.NewBuiltin("http.get", run.httpGet),
+			"post":    starlark.NewBuiltin("http.post", run.httpPost),
+			"request": starlark.NewBuiltin("http.request", run.httpRequest),
+		}},
+		"dns": &starlarkstruct.Module{Name: "dns", Members: starlark.StringDict{
+			"resolve": starlark.NewBuiltin("dns.resolve", run.dnsResolve),
+		}},
+		"tcp": &starlarkstruct.Module{Name: "tcp", Members: starlark.StringDict{
+			"connect": starlark.NewBuiltin("tcp.connect", run.tcpConnect),
+		}},
+	}
+}
+
+func (r *scriptRun) httpGet(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var rawURL string
+	var headers *starlark.Dict
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &rawURL, "headers?", &headers); err != nil {
+		return nil, err
+	}
+	return r.doHTTP(b.Name(), http.MethodGet, rawURL, headers, "")
+}
+
+func (r *scriptRun) httpPost(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var rawURL, body string
+	var headers *starlark.Dict
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &rawURL, "body?", &body, "headers?", &headers); err != nil {
+		return nil, err
+	}
+	return r.doHTTP(b.Name(), http.MethodPost, rawURL, headers, body)
+}
+
+func (r *scriptRun) httpRequest(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var method, rawURL, body string
+	var headers *starlark.Dict
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "method", &method, "url", &rawURL, "body?", &body, "headers?", &headers); err != nil {
+		return nil, err
+	}
+	method = strings.ToUpper(method)
+	if !validMethod(method) {
+		return nil, fmt.Errorf("%s: método inválido %q", b.Name(), method)
+	}
+	return r.doHTTP(b.Name(), method, rawURL, headers, body)
+}
+
+// doHTTP faz o request e devolve struct(status, body, headers, elapsed_ms, error); falhas de rede
+// não interrompem o script, ficam em error (status 0)
+func (r *scriptRun) doHTTP(name, method, rawURL string, headers *starlark.Dict, body string) (starlark.Value, error) {
+	if err := r.call(name); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%s: url inválida %q (use http ou https)", name, rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, method, u.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	req.Header.Set("User-Agent", "Ratatoskr/1.0")
+	if headers != nil {
+		for _, item := range headers.Items() {
+			k, okK := starlark.AsString(item[0])
+			v, okV := starlark.AsString(item[1])
+			if !okK || !okV {
+				return nil, fmt.Errorf("%s: headers deve ser um dict de strings", name)
+			}
+			req.Header.Set(k, v)
+		}
+	}
+
+	start := time.Now()
+	resp, err := scriptHTTPClient.Do(req)
+	if err != nil {
+		return scriptResponse(starlark.StringDict{"status": starlark.MakeInt(0), "body": starlark.String(""), "headers": new(starlark.Dict)}, time.Since(start), err), nil
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+
+	respHeaders := new(starlark.Dict)
+	for k := range resp.Header {
+		_ = respHeaders.SetKey(starlark.String(strings.ToLower(k)), starlark.String(resp.Header.Get(k)))
+	}
+	return scriptResponse(starlark.StringDict{
+		"status":  starlark.MakeInt(resp.StatusCode),
+		"body":    starlark.String(data),
+		"headers": respHeaders,
+	}, time.Since(start), err), nil
+}
+
+// dnsResolve consulta um registro e devolve struct(answers, rcode, elapsed_ms, error)
+func (r *scriptRun) dnsResolve(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, recordType, resolver, protocol string
+	recordType = "A"
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "type?", &recordType, "resolver?", &resolver, "protocol?", &protocol); err != nil {
+		return nil, err
+	}
+	qtype, ok := dnsRecordTypes[strings.ToUpper(recordType)]
+	if !ok {
+		return nil, fmt.Errorf("%s: tipo de registro não suportado %q", b.Name(), recordType)
+	}
+	if protocol != "" && protocol != "udp" && protocol != "tcp" && protocol != "doh" {
+		return nil, fmt.Errorf("%s: protocol deve ser udp, tcp ou doh", b.Name())
+	}
+	if err := r.call(b.Name()); err != nil {
+		return nil, err
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+
+	start := time.Now()
+	resp, err := exchangeDNS(r.ctx, msg, &entities.DNSCheck{Resolver: resolver, Protocol: protocol})
+	fields := starlark.StringDict{"answers": starlark.NewList(nil), "rcode": starlark.String("")}
+	if err == nil {
+		var answers []starlark.Value
+		for _, a := range dnsAnswers(resp, qtype) {
+			answers = append(answers, starlark.String(a))
+		}
+		fields["answers"] = starlark.NewList(answers)
+		fields["rcode"] = starlark.String(dns.RcodeToString[resp.Rcode])
+	}
+	return scriptResponse(fields, time.Since(start), err), nil
+}
+
+// tcpConnect conecta em host:port, opcionalmente envia um payload e lê o banner;
+// devolve struct(connected, banner, elapsed_ms, error)
+func (r *scriptRun) tcpConnect(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var host, send string
+	var port int
+	read := false
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "host", &host, "port", &port, "send?", &send, "read?", &read); err != nil {
+		return nil, err
+	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("%s: porta inválida %d", b.Name(), port)
+	}
+	if err := r.call(b.Name()); err != nil {
+		return nil, err
+	}
+
+	fields := starlark.StringDict{"connected": starlark.False, "banner": starlark.String("")}
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(r.ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return scriptResponse(fields, time.Since(start), err), nil
+	}
+	defer conn.Close()
+	fields["connected"] = starlark.True
+	if deadline, ok := r.ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if send != "" {
+		if _, err := conn.Write([]byte(unescape(send))); err != nil {
+			return scriptResponse(fields, time.Since(start), err), nil
+		}
+	}
+	if send != "" || read {
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		fields["banner"] = starlark.String(bytes.TrimRight(buf[:n], "\x00"))
+		if err != nil && n == 0 {
+			return scriptResponse(fields, time.Since(start), err), nil
+		}
+	}
+	return scriptResponse(fields, time.Since(start), nil), nil
+}
+
+func scriptResponse(fields starlark.StringDict, elapsed time.Duration, err error) *starlarkstruct.Struct {
+	fields["elapsed_ms"] = starlark.Float(milliseconds(elapsed))
+	fields["error"] = starlark.None
+	if err != nil {
+		fields["error"] = starlark.String(err.Error())
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, fields)
+}
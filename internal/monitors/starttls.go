@@ -0,0 +1,219 @@
+package monitors
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+)
+
+// startTLSPorts são as portas padrão de cada protocolo com STARTTLS
+var startTLSPorts = map[string]int{
+	"smtp":     587,
+	"imap":     143,
+	"pop3":     110,
+	"ftp":      21,
+	"ldap":     389,
+	"xmpp":     5222,
+	"postgres": 5432,
+}
+
+// startTLS negocia o upgrade para TLS no protocolo informado; ao retornar sem erro a conexão está pronta para o handshake
+func startTLS(conn net.Conn, protocol, host string) error {
+	switch protocol {
+	case "smtp":
+		return startTLSSMTP(conn)
+	case "imap":
+		return startTLSIMAP(conn)
+	case "pop3":
+		return startTLSPOP3(conn)
+	case "ftp":
+		return startTLSFTP(conn)
+	case "ldap":
+		return startTLSLDAP(conn)
+	case "xmpp":
+		return startTLSXMPP(conn, host)
+	case "postgres":
+		return startTLSPostgres(conn)
+	}
+	return fmt.Errorf("protocolo starttls desconhecido: %s", protocol)
+}
+
+func startTLSSMTP(conn net.Conn) error {
+	tp := textproto.NewConn(conn)
+	if _, _, err := tp.ReadResponse(220); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := tp.PrintfLine("EHLO ratatoskr"); err != nil {
+		return err
+	}
+	_, msg, err := tp.ReadResponse(250)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if !strings.Contains(strings.ToUpper(msg), "STARTTLS") {
+		return errors.New("smtp: o servidor não anuncia STARTTLS")
+	}
+	if err := tp.PrintfLine("STARTTLS"); err != nil {
+		return err
+	}
+	if _, _, err := tp.ReadResponse(220); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+func startTLSIMAP(conn net.Conn) error {
+	tp := textproto.NewConn(conn)
+	greeting, err := tp.ReadLine()
+	if err != nil {
+		return fmt.Errorf("imap: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("imap: saudação inesperada: %s", truncateBanner(greeting))
+	}
+	if err := tp.PrintfLine("a1 STARTTLS"); err != nil {
+		return err
+	}
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return fmt.Errorf("imap: %w", err)
+		}
+		if strings.HasPrefix(line, "a1 ") {
+			if !strings.HasPrefix(line, "a1 OK") {
+				return fmt.Errorf("imap: STARTTLS recusado: %s", truncateBanner(line))
+			}
+			return nil
+		}
+	}
+}
+
+func startTLSPOP3(conn net.Conn) error {
+	tp := textproto.NewConn(conn)
+	if err := expectPrefix(tp, "+OK", "pop3"); err != nil {
+		return err
+	}
+	if err := tp.PrintfLine("STLS"); err != nil {
+		return err
+	}
+	return expectPrefix(tp, "+OK", "pop3")
+}
+
+func startTLSFTP(conn net.Conn) error {
+	tp := textproto.NewConn(conn)
+	if _, _, err := tp.ReadResponse(220); err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+	if err := tp.PrintfLine("AUTH TLS"); err != nil {
+		return err
+	}
+	if _, _, err := tp.ReadResponse(234); err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+	return nil
+}
+
+// ldapStartTLSRequest é um ExtendedRequest (messageID 1) com o OID de StartTLS (RFC 4511)
+var ldapStartTLSRequest = append([]byte{0x30, 0x1d, 0x02, 0x01, 0x01, 0x77, 0x18, 0x80, 0x16}, "1.3.6.1.4.1.1466.20037"...)
+
+func startTLSLDAP(conn net.Conn) error {
+	if _, err := conn.Write(ldapStartTLSRequest); err != nil {
+		return err
+	}
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return fmt.Errorf("ldap: %w", err)
+	}
+	// ExtendedResponse (0x78) seguido do resultCode como ENUMERATED (0x0a 0x01 <code>)
+	resp := buf[:n]
+	i := bytes.IndexByte(resp, 0x78)
+	if i < 0 {
+		return errors.New("ldap: resposta inesperada ao StartTLS")
+	}
+	j := bytes.Index(resp[i:], []byte{0x0a, 0x01})
+	if j < 0 || i+j+2 >= len(resp) {
+		return errors.New("ldap: resposta inesperada ao StartTLS")
+	}
+	if code := resp[i+j+2]; code != 0 {
+		return fmt.Errorf("ldap: StartTLS recusado (resultCode %d)", code)
+	}
+	return nil
+}
+
+func startTLSXMPP(conn net.Conn, host string) error {
+	header := fmt.Sprintf("<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", host)
+	if _, err := io.WriteString(conn, header); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(conn)
+	features, err := readUntil(reader, "</stream:features>")
+	if err != nil {
+		return fmt.Errorf("xmpp: %w", err)
+	}
+	if !strings.Contains(features, "urn:ietf:params:xml:ns:xmpp-tls") {
+		return errors.New("xmpp: o servidor não anuncia STARTTLS")
+	}
+	if _, err := io.WriteString(conn, "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"); err != nil {
+		return err
+	}
+	answer, err := readUntil(reader, "/>")
+	if err != nil {
+		return fmt.Errorf("xmpp: %w", err)
+	}
+	if !strings.Contains(answer, "<proceed") {
+		return fmt.Errorf("xmpp: STARTTLS recusado: %s", truncateBanner(answer))
+	}
+	return nil
+}
+
+// startTLSPostgres envia o SSLRequest do protocolo do PostgreSQL (tamanho 8, código 80877103)
+func startTLSPostgres(conn net.Conn) error {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint32(msg[0:4], 8)
+	binary.BigEndian.PutUint32(msg[4:8], 80877103)
+	if _, err := conn.Write(msg); err != nil {
+		return err
+	}
+	answer := make([]byte, 1)
+	if _, err := io.ReadFull(conn, answer); err != nil {
+		return fmt.Errorf("postgres: %w", err)
+	}
+	if answer[0] != 'S' {
+		return errors.New("postgres: o servidor não aceita SSL")
+	}
+	return nil
+}
+
+func expectPrefix(tp *textproto.Conn, prefix, protocol string) error {
+	line, err := tp.ReadLine()
+	if err != nil {
+		return fmt.Errorf("%s: %w", protocol, err)
+	}
+	if !strings.HasPrefix(line, prefix) {
+		return fmt.Errorf("%s: resposta inesperada: %s", protocol, truncateBanner(line))
+	}
+	return nil
+}
+
+// readUntil lê do stream até encontrar marker, limitado a maxBannerSize
+func readUntil(r *bufio.Reader, marker string) (string, error) {
+	var sb strings.Builder
+	for sb.Len() < maxBannerSize {
+		b, err := r.ReadByte()
+		if err != nil {
+			return sb.String(), err
+		}
+		sb.WriteByte(b)
+		if b == marker[len(marker)-1] && strings.HasSuffix(sb.String(), marker) {
+			return sb.String(), nil
+		}
+	}
+	return sb.String(), errors.New("resposta muito grande")
+}
@@ -0,0 +1,134 @@
+package monitors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+// maxBannerSize limita quanto da resposta é lido ao procurar o banner esperado
+const maxBannerSize = 64 * 1024
+
+func validateTCP(e *entities.Endpoint) error {
+	if e.Port <= 0 || e.Port > 65535 {
+		return errors.New("port é obrigatório para monitores tcp")
+	}
+	if e.TCP != nil && e.TCP.ExpectRegex != "" {
+		if _, err := regexp.Compile(e.TCP.ExpectRegex); err != nil {
+			return fmt.Errorf("tcp.expect_regex inválido: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkTCP conecta em Domain:Port, mede o tempo de conexão e valida o banner quando configurado
+func checkTCP(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	addr := net.JoinHostPort(hostOnly(e.Domain), strconv.Itoa(e.Port))
+
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	connectTime := time.Since(start)
+	result.ResponseTime = connectTime
+	result.Metrics["connect_ms"] = milliseconds(connectTime)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	cfg := e.TCP
+	if cfg == nil {
+		result.Status = entities.StatusOnline
+		return result
+	}
+
+	if cfg.Send != "" {
+		if _, err := conn.Write([]byte(unescape(cfg.Send))); err != nil {
+			result.ErrorMessage = fmt.Sprintf("erro ao enviar payload: %v", err)
+			return result
+		}
+	}
+
+	if cfg.Expect == "" && cfg.ExpectRegex == "" {
+		result.ResponseTime = time.Since(start)
+		result.Status = entities.StatusOnline
+		return result
+	}
+
+	var re *regexp.Regexp
+	if cfg.ExpectRegex != "" {
+		if re, err = regexp.Compile(cfg.ExpectRegex); err != nil {
+			result.ErrorMessage = fmt.Sprintf("tcp.expect_regex inválido: %v", err)
+			return result
+		}
+	}
+	matches := func(banner string) bool {
+		if cfg.Expect != "" && !strings.Contains(banner, cfg.Expect) {
+			return false
+		}
+		return re == nil || re.MatchString(banner)
+	}
+
+	// lê até a resposta satisfazer as asserções, a conexão fechar ou o timeout estourar
+	var banner []byte
+	buf := make([]byte, 4096)
+	for len(banner) < maxBannerSize {
+		n, readErr := conn.Read(buf)
+		banner = append(banner, buf[:n]...)
+		if matches(string(banner)) {
+			result.ResponseTime = time.Since(start)
+			result.Metrics["response_ms"] = milliseconds(result.ResponseTime)
+			result.Status = entities.StatusOnline
+			return result
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	result.ResponseTime = time.Since(start)
+	result.ErrorMessage = fmt.Sprintf("resposta não corresponde ao esperado: %q", truncateBanner(string(banner)))
+	return result
+}
+
+// hostOnly remove esquema, porta e path do Domain, que pode ter sido cadastrado como URL
+func hostOnly(domain string) string {
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		return host
+	}
+	return strings.Trim(domain, "[]")
+}
+
+// unescape interpreta sequências como \r\n e \x00 no payload configurado
+func unescape(s string) string {
+	if unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(s, `"`, `\"`) + `"`); err == nil {
+		return unquoted
+	}
+	return s
+}
+
+func truncateBanner(s string) string {
+	if len(s) > 200 {
+		return s[:200] + "…"
+	}
+	return s
+}
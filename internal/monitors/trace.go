@@ -0,0 +1,77 @@
+package monitors
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+// requestTracer registra os instantes de cada fase do request. Em redirects vale a última conexão.
+type requestTracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+// withTrace anexa o tracer ao contexto do request
+func withTrace(req *http.Request) (*http.Request, *requestTracer) {
+	t := &requestTracer{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.mark(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.mark(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+func (t *requestTracer) mark(field *time.Time) {
+	t.mu.Lock()
+	*field = time.Now()
+	t.mu.Unlock()
+}
+
+// timings calcula a duração de cada fase; end é o instante em que o body terminou de ser lido
+func (t *requestTracer) timings(end time.Time) *entities.RequestTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &entities.RequestTimings{
+		DNSLookup:       phase(t.dnsStart, t.dnsDone),
+		TCPConnect:      phase(t.connectStart, t.connectDone),
+		TLSHandshake:    phase(t.tlsStart, t.tlsDone),
+		TimeToFirstByte: phase(t.wroteRequest, t.firstByte),
+		ContentTransfer: phase(t.firstByte, end),
+		Total:           milliseconds(end.Sub(t.start)),
+		ConnReused:      t.reused,
+	}
+}
+
+// phase devolve a duração em ms, ou zero quando a fase não aconteceu
+func phase(from, to time.Time) float64 {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return milliseconds(to.Sub(from))
+}
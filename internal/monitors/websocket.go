@@ -0,0 +1,181 @@
+package monitors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/gorilla/websocket"
+)
+
+func validateWebSocket(e *entities.Endpoint) error {
+	if err := validateAuth(e.Authentication); err != nil {
+		return err
+	}
+	cfg := e.WebSocket
+	if cfg == nil {
+		return nil
+	}
+	if cfg.ReceiveTimeout < 0 {
+		return errors.New("websocket.receive_timeout não pode ser negativo")
+	}
+	if cfg.Expect != nil {
+		if cfg.Expect.Type != "body" && cfg.Expect.Type != "json" {
+			return errors.New("websocket.expect.type deve ser body ou json")
+		}
+		if err := validateAssertions([]entities.Assertion{*cfg.Expect}); err != nil {
+			return fmt.Errorf("websocket.expect: %w", err)
+		}
+	}
+	return nil
+}
+
+// webSocketURL converte o esquema da URL do endpoint: http -> ws e https -> wss
+func webSocketURL(e *entities.Endpoint) string {
+	u := e.URL()
+	switch {
+	case strings.HasPrefix(u, "https://"):
+		return "wss://" + strings.TrimPrefix(u, "https://")
+	case strings.HasPrefix(u, "http://"):
+		return "ws://" + strings.TrimPrefix(u, "http://")
+	}
+	return u
+}
+
+// checkWebSocket faz o upgrade, envia a mensagem configurada e espera a resposta esperada,
+// medindo o handshake e a latência até a mensagem (round trip quando há send)
+func checkWebSocket(ctx context.Context, e *entities.Endpoint) CheckResult {
+	result := newResult()
+	cfg := e.WebSocket
+	if cfg == nil {
+		cfg = &entities.WebSocketCheck{}
+	}
+
+	target, header, err := webSocketRequest(e, cfg)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = cfg.Subprotocols
+	if e.Authentication != nil && e.Authentication.Type == entities.AuthMTLS {
+		if dialer.TLSClientConfig, err = mtlsConfig(e.Authentication); err != nil {
+			result.ErrorMessage = err.Error()
+			return result
+		}
+	}
+
+	start := time.Now()
+	conn, resp, err := dialer.DialContext(ctx, target, header)
+	handshake := time.Since(start)
+	result.ResponseTime = handshake
+	result.Metrics["handshake_ms"] = milliseconds(handshake)
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			result.ErrorMessage = fmt.Sprintf("upgrade recusado: HTTP %d", resp.StatusCode)
+		} else {
+			result.ErrorMessage = err.Error()
+		}
+		return result
+	}
+	defer conn.Close()
+
+	if len(cfg.Subprotocols) > 0 && conn.Subprotocol() == "" {
+		result.ErrorMessage = "o servidor não aceitou nenhum dos subprotocolos: " + strings.Join(cfg.Subprotocols, ", ")
+		return result
+	}
+
+	if cfg.Send == "" && cfg.Expect == nil {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		result.Status = entities.StatusOnline
+		return result
+	}
+
+	deadline := time.Now().Add(Timeout(e))
+	if cfg.ReceiveTimeout > 0 {
+		deadline = time.Now().Add(time.Duration(cfg.ReceiveTimeout) * time.Millisecond)
+	}
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetWriteDeadline(deadline)
+	_ = conn.SetReadDeadline(deadline)
+
+	sent := time.Now()
+	if cfg.Send != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(cfg.Send)); err != nil {
+			result.ErrorMessage = fmt.Sprintf("erro ao enviar mensagem: %v", err)
+			return result
+		}
+	}
+
+	// lê mensagens até uma satisfazer o expect (sem expect, basta a primeira) ou o prazo acabar
+	var last entities.AssertionResult
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			result.ResponseTime = time.Since(start)
+			switch {
+			case last.Message != "":
+				result.Assertions = []entities.AssertionResult{last}
+				result.ErrorMessage = "nenhuma mensagem satisfez o expect: " + last.Message
+			case isTimeout(err):
+				result.ErrorMessage = "nenhuma mensagem recebida dentro do timeout"
+			default:
+				result.ErrorMessage = fmt.Sprintf("erro ao ler mensagem: %v", err)
+			}
+			return result
+		}
+		if cfg.Expect == nil {
+			break
+		}
+		last = evaluateAssertions([]entities.Assertion{*cfg.Expect}, &http.Response{Header: http.Header{}}, msg, int64(len(msg)), time.Since(sent))[0]
+		if last.Passed {
+			result.Assertions = []entities.AssertionResult{last}
+			break
+		}
+	}
+
+	roundTrip := time.Since(sent)
+	if cfg.Send != "" {
+		result.Metrics["round_trip_ms"] = milliseconds(roundTrip)
+	} else {
+		result.Metrics["receive_ms"] = milliseconds(roundTrip)
+	}
+	result.ResponseTime = time.Since(start)
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	result.Status = entities.StatusOnline
+	return result
+}
+
+// webSocketRequest monta a URL e os headers do upgrade, aplicando a autenticação do endpoint
+func webSocketRequest(e *entities.Endpoint, cfg *entities.WebSocketCheck) (string, http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, webSocketURL(e), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if req.URL.Scheme != "ws" && req.URL.Scheme != "wss" {
+		return "", nil, fmt.Errorf("esquema inválido para websocket: %s", req.URL.Scheme)
+	}
+	req.Header.Set("User-Agent", "Ratatoskr/1.0")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if err := applyAuth(req, e.Authentication); err != nil {
+		return "", nil, err
+	}
+	return req.URL.String(), req.Header, nil
+}
+
+func isTimeout(err error) bool {
+	var netErr interface{ Timeout() bool }
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,189 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	heldKeyPrefix      = "ratatoskr:notifications:held:"
+	coalescedKeyPrefix = "ratatoskr:notifications:coalesced:"
+	rateKeyPrefix      = "ratatoskr:notifications:rate:"
+
+	// maxSummaryItems limita quantas mensagens aparecem em um resumo
+	maxSummaryItems = 20
+)
+
+// deliver aplica o horário silencioso e o rate limit do canal antes de enviar a mensagem.
+// Mensagens retidas ou acima do limite ficam no Redis até o próximo FlushPending.
+func (d *Dispatcher) deliver(ctx context.Context, ch entities.AlertChannel, text string, event Event) error {
+	now := time.Now()
+
+	if ch.QuietHours != nil && !event.Type.IsCritical() && ch.QuietHours.Active(now) {
+		return d.enqueue(ctx, heldKeyPrefix+channelKey(ch), text)
+	}
+
+	allowed, err := d.allow(ctx, ch, now)
+	if err != nil {
+		// Redis indisponível não deve impedir a entrega de alertas
+		log.Warn().Err(err).Str("channel", ch.Name).Msg("Erro ao verificar rate limit do canal")
+		allowed = true
+	}
+	if !allowed {
+		log.Debug().Str("channel", ch.Name).Msg("Rate limit do canal atingido, mensagem agrupada")
+		return d.enqueue(ctx, coalescedKeyPrefix+channelKey(ch), text)
+	}
+
+	return d.send(ch, text, event)
+}
+
+// allow consome uma mensagem das janelas de rate limit do canal, compartilhadas entre réplicas via Redis
+func (d *Dispatcher) allow(ctx context.Context, ch entities.AlertChannel, now time.Time) (bool, error) {
+	if ch.RateLimit == nil || d.rdb == nil {
+		return true, nil
+	}
+
+	windows := []struct {
+		name  string
+		size  time.Duration
+		limit int
+	}{
+		{"m", time.Minute, ch.RateLimit.PerMinute},
+		{"h", time.Hour, ch.RateLimit.PerHour},
+	}
+
+	var taken []string
+	for _, w := range windows {
+		if w.limit <= 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s%s:%s:%d", rateKeyPrefix, channelKey(ch), w.name, now.Unix()/int64(w.size.Seconds()))
+
+		// INCR e EXPIRE juntos, para que uma falha entre os dois não deixe a chave sem expiração
+		var incr *redis.IntCmd
+		if _, err := d.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			incr = pipe.Incr(ctx, key)
+			pipe.Expire(ctx, key, 2*w.size)
+			return nil
+		}); err != nil {
+			return false, err
+		}
+		count := incr.Val()
+		taken = append(taken, key)
+
+		if count > int64(w.limit) {
+			// devolve o que foi consumido, a mensagem não será enviada agora
+			for _, k := range taken {
+				d.rdb.Decr(ctx, k)
+			}
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, key, text string) error {
+	if d.rdb == nil {
+		return fmt.Errorf("redis não configurado para reter notificações")
+	}
+	return d.rdb.RPush(ctx, key, text).Err()
+}
+
+// FlushPending entrega, como uma única mensagem por canal, as notificações retidas durante o
+// horário silencioso (após o fim da janela) e as agrupadas pelo rate limit (quando houver cota).
+func (d *Dispatcher) FlushPending(ctx context.Context) error {
+	if d.rdb == nil {
+		return nil
+	}
+
+	channels, err := d.channels.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	channels = append(channels, defaultChannels(d.cfg)...)
+
+	now := time.Now()
+	for _, ch := range channels {
+		if !ch.Enabled {
+			continue
+		}
+		if ch.QuietHours == nil || !ch.QuietHours.Active(now) {
+			if err := d.flush(ctx, ch, heldKeyPrefix+channelKey(ch), "📬 %d notificação(ões) retida(s) durante o horário silencioso", now); err != nil {
+				log.Error().Err(err).Str("channel", ch.Name).Msg("Erro ao entregar notificações retidas")
+			}
+		}
+		if err := d.flush(ctx, ch, coalescedKeyPrefix+channelKey(ch), "⚠️ %d notificação(ões) agrupada(s) pelo limite de envio", now); err != nil {
+			log.Error().Err(err).Str("channel", ch.Name).Msg("Erro ao entregar notificações agrupadas")
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) flush(ctx context.Context, ch entities.AlertChannel, key, title string, now time.Time) error {
+	pending, err := d.rdb.LLen(ctx, key).Result()
+	if err != nil || pending == 0 {
+		return err
+	}
+
+	allowed, err := d.allow(ctx, ch, now)
+	if err != nil || !allowed {
+		return err
+	}
+
+	// LRANGE + DEL na mesma transação para que outra réplica não entregue as mesmas mensagens
+	var items *redis.StringSliceCmd
+	_, err = d.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		items = pipe.LRange(ctx, key, 0, -1)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(items.Val()) == 0 {
+		return nil
+	}
+
+	if err := d.send(ch, summarize(title, items.Val()), Event{Type: entities.EventReminder}); err != nil {
+		// devolve as mensagens ao início da fila, na ordem original, para a próxima tentativa
+		messages := items.Val()
+		requeue := make([]interface{}, len(messages))
+		for i, msg := range messages {
+			requeue[len(messages)-1-i] = msg
+		}
+		if pushErr := d.rdb.LPush(ctx, key, requeue...).Err(); pushErr != nil {
+			log.Error().Err(pushErr).Str("channel", ch.Name).Int("messages", len(messages)).Msg("Erro ao devolver notificações à fila")
+		}
+		return err
+	}
+	return nil
+}
+
+// summarize monta o resumo com a primeira linha de cada mensagem
+func summarize(title string, messages []string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(title, len(messages)))
+	for i, msg := range messages {
+		if i == maxSummaryItems {
+			b.WriteString(fmt.Sprintf("\n… e mais %d", len(messages)-maxSummaryItems))
+			break
+		}
+		line, _, _ := strings.Cut(msg, "\n")
+		b.WriteString("\n• " + truncate(200, line))
+	}
+	return b.String()
+}
+
+// channelKey identifica o canal nas chaves do Redis; canais do arquivo de configuração não têm ID
+func channelKey(ch entities.AlertChannel) string {
+	if ch.ID.IsZero() {
+		return "default-" + ch.Type
+	}
+	return ch.ID.Hex()
+}
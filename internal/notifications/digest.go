@@ -0,0 +1,309 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// digestTopN limita as listas de piores endpoints e de variação de latência
+	digestTopN = 5
+	// digestSSLWarningDays é o prazo para um certificado aparecer como "expirando em breve"
+	digestSSLWarningDays = 30
+)
+
+// DigestReporter compila e envia os digests agendados
+type DigestReporter struct {
+	digests    repositories.DigestRepository
+	endpoints  repositories.EndpointRepository
+	incidents  repositories.IncidentRepository
+	history    repositories.HistoryRepository
+	groups     repositories.AlertGroupRepository
+	dispatcher *Dispatcher
+}
+
+func NewDigestReporter(digests repositories.DigestRepository, endpoints repositories.EndpointRepository, incidents repositories.IncidentRepository, history repositories.HistoryRepository, groups repositories.AlertGroupRepository, dispatcher *Dispatcher) *DigestReporter {
+	return &DigestReporter{
+		digests:    digests,
+		endpoints:  endpoints,
+		incidents:  incidents,
+		history:    history,
+		groups:     groups,
+		dispatcher: dispatcher,
+	}
+}
+
+// RunDue envia os digests cujo horário agendado já passou e ainda não foram enviados
+func (r *DigestReporter) RunDue(ctx context.Context) error {
+	digests, err := r.digests.FindEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, d := range digests {
+		if !d.Due(now) {
+			continue
+		}
+		scheduled := d.ScheduledAt(now)
+		claimed, err := r.digests.Claim(ctx, d.ID, scheduled, now)
+		if err != nil || !claimed {
+			continue
+		}
+
+		// o claim evita o envio duplicado entre réplicas; se a compilação falhar ou nenhum canal receber o
+		// digest ele é desfeito para nova tentativa no próximo ciclo. Com ao menos um canal entregue o claim
+		// fica, senão um canal quebrado faria os demais receberem o mesmo digest a cada ciclo
+		text, err := r.Build(ctx, &d, scheduled)
+		if err != nil {
+			log.Error().Err(err).Str("digest", d.Name).Msg("Erro ao compilar digest")
+			r.release(ctx, &d, now)
+			continue
+		}
+		sent, err := r.dispatcher.Broadcast(ctx, d.ChannelIDs, text)
+		if err != nil && sent == 0 {
+			log.Error().Err(err).Str("digest", d.Name).Msg("Erro ao enviar digest")
+			r.release(ctx, &d, now)
+			continue
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("digest", d.Name).Int("sent", sent).Msg("Digest não entregue em todos os canais")
+			continue
+		}
+		log.Info().Str("digest", d.Name).Msg("📊 Digest enviado")
+	}
+	return nil
+}
+
+func (r *DigestReporter) release(ctx context.Context, d *entities.Digest, claimedAt time.Time) {
+	if err := r.digests.Release(ctx, d.ID, claimedAt, d.LastSentAt); err != nil {
+		log.Error().Err(err).Str("digest", d.Name).Msg("Erro ao liberar digest para nova tentativa")
+	}
+}
+
+// Build compila o relatório do período que termina em end
+func (r *DigestReporter) Build(ctx context.Context, d *entities.Digest, end time.Time) (string, error) {
+	start := end.Add(-d.Period())
+	prevStart := start.Add(-d.Period())
+
+	endpoints, err := r.endpoints.FindAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	groups, err := r.groups.FindAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	current, err := r.history.Stats(ctx, start, end)
+	if err != nil {
+		return "", err
+	}
+	previous, err := r.history.Stats(ctx, prevStart, start)
+	if err != nil {
+		return "", err
+	}
+	incidents, err := r.incidents.FindStartedBetween(ctx, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	groups = filterGroups(groups, d.GroupIDs)
+	endpoints = filterEndpoints(endpoints, groups, len(d.GroupIDs) > 0)
+
+	stats := indexStats(current)
+	prevStats := indexStats(previous)
+	incidentsByEndpoint := make(map[primitive.ObjectID]int)
+	for _, i := range incidents {
+		incidentsByEndpoint[i.EndpointID]++
+	}
+
+	var b strings.Builder
+	title := "Resumo diário"
+	if d.Frequency == entities.DigestWeekly {
+		title = "Resumo semanal"
+	}
+	b.WriteString(fmt.Sprintf("📊 *%s: %s*\n", title, d.Name))
+	b.WriteString(fmt.Sprintf("%s a %s (%s)\n", start.Format("02/01 15:04"), end.Format("02/01 15:04"), end.Location()))
+
+	writeGroupsSection(&b, groups, endpoints, stats, incidentsByEndpoint, len(d.GroupIDs) == 0)
+	writeWorstSection(&b, endpoints, stats, incidentsByEndpoint)
+	writeSSLSection(&b, endpoints)
+	writeLatencySection(&b, endpoints, stats, prevStats)
+
+	return strings.TrimSpace(b.String()), nil
+}
+
+func writeGroupsSection(b *strings.Builder, groups []entities.AlertGroup, endpoints []entities.Endpoint, stats map[primitive.ObjectID]entities.EndpointStats, incidents map[primitive.ObjectID]int, includeUngrouped bool) {
+	b.WriteString("\n*Uptime por grupo*\n")
+
+	line := func(name string, members []entities.Endpoint) {
+		var agg entities.EndpointStats
+		count := 0
+		for _, e := range members {
+			s := stats[e.ID]
+			agg.TotalChecks += s.TotalChecks
+			agg.SuccessfulChecks += s.SuccessfulChecks
+			count += incidents[e.ID]
+		}
+		b.WriteString(fmt.Sprintf("• %s: %.2f%% em %d endpoint(s), %d incidente(s)\n", name, agg.Uptime(), len(members), count))
+	}
+
+	for _, g := range groups {
+		var members []entities.Endpoint
+		for _, e := range endpoints {
+			if slices.Contains(e.AlertGroupIDs, g.ID) {
+				members = append(members, e)
+			}
+		}
+		line(g.Name, members)
+	}
+
+	if includeUngrouped {
+		var ungrouped []entities.Endpoint
+		for _, e := range endpoints {
+			if len(e.AlertGroupIDs) == 0 {
+				ungrouped = append(ungrouped, e)
+			}
+		}
+		if len(ungrouped) > 0 {
+			line("Sem grupo", ungrouped)
+		}
+	}
+}
+
+func writeWorstSection(b *strings.Builder, endpoints []entities.Endpoint, stats map[primitive.ObjectID]entities.EndpointStats, incidents map[primitive.ObjectID]int) {
+	ranked := make([]entities.Endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if stats[e.ID].TotalChecks > 0 {
+			ranked = append(ranked, e)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		si, sj := stats[ranked[i].ID], stats[ranked[j].ID]
+		if si.Uptime() != sj.Uptime() {
+			return si.Uptime() < sj.Uptime()
+		}
+		return si.AvgResponseTime > sj.AvgResponseTime
+	})
+	if len(ranked) > digestTopN {
+		ranked = ranked[:digestTopN]
+	}
+	if len(ranked) == 0 {
+		return
+	}
+
+	b.WriteString("\n*Piores endpoints*\n")
+	for i, e := range ranked {
+		s := stats[e.ID]
+		b.WriteString(fmt.Sprintf("%d. %s: %.2f%%, %d ms, %d incidente(s)\n", i+1, e.Name, s.Uptime(), s.AvgResponseTime.Milliseconds(), incidents[e.ID]))
+	}
+}
+
+func writeSSLSection(b *strings.Builder, endpoints []entities.Endpoint) {
+	var expiring []entities.Endpoint
+	for _, e := range endpoints {
+		if e.CheckSSL && !e.SSLData.ExpirationDate.IsZero() && e.SSLData.DaysLeft <= digestSSLWarningDays {
+			expiring = append(expiring, e)
+		}
+	}
+	if len(expiring) == 0 {
+		return
+	}
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].SSLData.DaysLeft < expiring[j].SSLData.DaysLeft
+	})
+
+	b.WriteString("\n*Certificados expirando*\n")
+	for _, e := range expiring {
+		if e.SSLData.Expired {
+			b.WriteString(fmt.Sprintf("• %s: expirado em %s\n", e.Domain, e.SSLData.ExpirationDate.Format("02/01/2006")))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("• %s: %d dia(s) (%s)\n", e.Domain, e.SSLData.DaysLeft, e.SSLData.ExpirationDate.Format("02/01/2006")))
+	}
+}
+
+func writeLatencySection(b *strings.Builder, endpoints []entities.Endpoint, current, previous map[primitive.ObjectID]entities.EndpointStats) {
+	type trend struct {
+		name     string
+		now      time.Duration
+		variance float64
+	}
+
+	var trends []trend
+	for _, e := range endpoints {
+		cur, prev := current[e.ID], previous[e.ID]
+		if cur.TotalChecks == 0 || prev.TotalChecks == 0 || prev.AvgResponseTime == 0 {
+			continue
+		}
+		variance := (float64(cur.AvgResponseTime) - float64(prev.AvgResponseTime)) / float64(prev.AvgResponseTime) * 100
+		trends = append(trends, trend{name: e.Name, now: cur.AvgResponseTime, variance: variance})
+	}
+	if len(trends) == 0 {
+		return
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		return math.Abs(trends[i].variance) > math.Abs(trends[j].variance)
+	})
+	if len(trends) > digestTopN {
+		trends = trends[:digestTopN]
+	}
+
+	b.WriteString("\n*Tendência de latência*\n")
+	for _, t := range trends {
+		arrow := "▲"
+		if t.variance < 0 {
+			arrow = "▼"
+		}
+		b.WriteString(fmt.Sprintf("• %s: %d ms (%s %.0f%% vs período anterior)\n", t.name, t.now.Milliseconds(), arrow, math.Abs(t.variance)))
+	}
+}
+
+// filterGroups mantém apenas os grupos selecionados no digest (todos quando ids é vazio)
+func filterGroups(groups []entities.AlertGroup, ids []primitive.ObjectID) []entities.AlertGroup {
+	if len(ids) == 0 {
+		return groups
+	}
+	var filtered []entities.AlertGroup
+	for _, g := range groups {
+		if slices.Contains(ids, g.ID) {
+			filtered = append(filtered, g)
+		}
+	}
+	return filtered
+}
+
+// filterEndpoints mantém os endpoints pertencentes aos grupos quando o digest é restrito a grupos
+func filterEndpoints(endpoints []entities.Endpoint, groups []entities.AlertGroup, restricted bool) []entities.Endpoint {
+	if !restricted {
+		return endpoints
+	}
+	var filtered []entities.Endpoint
+	for _, e := range endpoints {
+		for _, g := range groups {
+			if slices.Contains(e.AlertGroupIDs, g.ID) {
+				filtered = append(filtered, e)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func indexStats(stats []entities.EndpointStats) map[primitive.ObjectID]entities.EndpointStats {
+	index := make(map[primitive.ObjectID]entities.EndpointStats, len(stats))
+	for _, s := range stats {
+		index[s.EndpointID] = s
+	}
+	return index
+}
@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+func SendEmailMsg(cfg *config.AppConfig, subject, body string) error {
+	email := cfg.Alerts.Email
+	if email.Host == "" || len(email.To) == 0 {
+		return errors.New("email não configurado")
+	}
+
+	port := email.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(email.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if email.Username != "" {
+		auth = smtp.PlainAuth("", email.Username, email.Password, email.Host)
+	}
+
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", email.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(email.To, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject)))
+	msg.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(addr, auth, email.From, email.To, []byte(msg.String())); err != nil {
+		log.Error().Msgf("Failed to send email: %v", err)
+		return err
+	}
+	return nil
+}
+
+// emailMarkup são as marcações de Markdown das mensagens, que não fazem sentido no assunto
+var emailMarkup = strings.NewReplacer("*", "", "`", "", "\r", "")
+
+// emailSubject usa a primeira linha da mensagem, sem marcações, como assunto
+func emailSubject(text string) string {
+	subject, _, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(emailMarkup.Replace(subject))
+}
@@ -1,10 +1,29 @@
 package notifications
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Event - Evento de um endpoint que deve ser notificado nos canais de alerta
+type Event struct {
+	Type     entities.EventType
+	Endpoint *entities.Endpoint
+	Incident *entities.Incident
+	Message  string
+}
+
 func SendAlert(cfg *config.AppConfig, message, logType string) error {
 	err := SendTelegramMsg(cfg, message)
 	if err != nil {
@@ -17,6 +36,200 @@ func SendAlert(cfg *config.AppConfig, message, logType string) error {
 	return nil
 }
 
+// Dispatcher entrega eventos aos canais dos grupos de alerta do endpoint, renderizando
+// o template de cada canal. Sem grupos configurados usa os canais do arquivo de configuração.
+type Dispatcher struct {
+	cfg       *config.AppConfig
+	channels  repositories.AlertChannelRepository
+	groups    repositories.AlertGroupRepository
+	templates repositories.TemplateRepository
+	rdb       *redis.Client
+}
+
+func NewDispatcher(cfg *config.AppConfig, channels repositories.AlertChannelRepository, groups repositories.AlertGroupRepository, templates repositories.TemplateRepository, rdb *redis.Client) *Dispatcher {
+	return &Dispatcher{cfg: cfg, channels: channels, groups: groups, templates: templates, rdb: rdb}
+}
+
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) error {
+	if event.Endpoint != nil && event.Endpoint.IsMuted(time.Now()) {
+		log.Debug().Str("endpoint", event.Endpoint.Name).Msg("Endpoint silenciado, notificação ignorada")
+		return nil
+	}
+
+	groupIDs, channels, err := d.resolveChannels(ctx, event.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ch := range channels {
+		text := d.render(ctx, ch.ID, groupIDs, event)
+		if err := d.deliver(ctx, ch, text, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Broadcast envia um texto pronto (ex.: digests) aos canais informados, sem horário silencioso ou rate limit.
+// Devolve quantos canais receberam o texto junto com os erros dos que falharam
+func (d *Dispatcher) Broadcast(ctx context.Context, channelIDs []primitive.ObjectID, text string) (int, error) {
+	channels, err := d.channels.FindByIDs(ctx, channelIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, ch := range channels {
+		if !ch.Enabled {
+			continue
+		}
+		if err := d.send(ch, text, Event{Type: entities.EventReminder}); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// resolveChannels devolve os grupos habilitados do endpoint e seus canais habilitados
+func (d *Dispatcher) resolveChannels(ctx context.Context, endpoint *entities.Endpoint) ([]primitive.ObjectID, []entities.AlertChannel, error) {
+	if endpoint == nil || len(endpoint.AlertGroupIDs) == 0 {
+		return nil, defaultChannels(d.cfg), nil
+	}
+
+	groups, err := d.groups.FindByIDs(ctx, endpoint.AlertGroupIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var groupIDs, channelIDs []primitive.ObjectID
+	for _, g := range groups {
+		if !g.Enabled {
+			continue
+		}
+		groupIDs = append(groupIDs, g.ID)
+		channelIDs = append(channelIDs, g.ChannelIDs...)
+	}
+	if len(channelIDs) == 0 {
+		return groupIDs, nil, nil
+	}
+
+	found, err := d.channels.FindByIDs(ctx, channelIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var channels []entities.AlertChannel
+	for _, ch := range found {
+		if ch.Enabled {
+			channels = append(channels, ch)
+		}
+	}
+	return groupIDs, channels, nil
+}
+
+// render usa o template do canal/grupo para o evento, com fallback para o template padrão
+func (d *Dispatcher) render(ctx context.Context, channelID primitive.ObjectID, groupIDs []primitive.ObjectID, event Event) string {
+	data := NewTemplateData(event)
+
+	if !channelID.IsZero() || len(groupIDs) > 0 {
+		if t, err := d.templates.Resolve(ctx, channelID, groupIDs, event.Type); err == nil {
+			text, err := RenderTemplate(t.Body, data)
+			if err == nil {
+				return text
+			}
+			log.Warn().Err(err).Str("template_id", t.ID.Hex()).Msg("Erro ao renderizar template, usando o padrão")
+		}
+	}
+
+	text, err := RenderTemplate(DefaultTemplates[event.Type], data)
+	if err != nil || text == "" {
+		return event.Message
+	}
+	return text
+}
+
+func (d *Dispatcher) send(ch entities.AlertChannel, text string, event Event) error {
+	cfg := channelConfig(d.cfg, ch)
+	withIncident := event.Incident != nil && event.Endpoint != nil
+
+	switch ch.Type {
+	case "slack":
+		if withIncident {
+			return SendSlackIncident(cfg, event.Endpoint, event.Incident, text)
+		}
+		return SendSlackMsg(cfg, configureSlackAttachment(text, eventLogType(event.Type)))
+	case "telegram":
+		if withIncident {
+			return SendTelegramIncident(cfg, event.Endpoint, event.Incident, text)
+		}
+		return SendTelegramMsg(cfg, text)
+	case "email":
+		return SendEmailMsg(cfg, emailSubject(text), text)
+	default:
+		return fmt.Errorf("tipo de canal não suportado: %s", ch.Type)
+	}
+}
+
+// channelConfig aplica as credenciais do canal (AlertChannel.Config) sobre a configuração global
+func channelConfig(base *config.AppConfig, ch entities.AlertChannel) *config.AppConfig {
+	cfg := *base
+	str := func(key string) string {
+		v, _ := ch.Config[key].(string)
+		return v
+	}
+
+	switch ch.Type {
+	case "slack":
+		if v := str("token"); v != "" {
+			cfg.Alerts.Slack.Token = v
+		}
+		if v := str("channel"); v != "" {
+			cfg.Alerts.Slack.Channel = v
+		}
+	case "telegram":
+		if v := str("bot_token"); v != "" {
+			cfg.Alerts.Telegram.BotToken = v
+		}
+		if v := str("chat_id"); v != "" {
+			cfg.Alerts.Telegram.ChatID = v
+		}
+	case "email":
+		if v := str("to"); v != "" {
+			cfg.Alerts.Email.To = strings.Split(v, ",")
+		}
+	}
+	return &cfg
+}
+
+// defaultChannels monta canais a partir da seção alerting do arquivo de configuração
+func defaultChannels(cfg *config.AppConfig) []entities.AlertChannel {
+	var channels []entities.AlertChannel
+	if cfg.Alerts.Slack.Token != "" {
+		channels = append(channels, entities.AlertChannel{Type: "slack", Name: "slack", Enabled: true})
+	}
+	if cfg.Alerts.Telegram.BotToken != "" {
+		channels = append(channels, entities.AlertChannel{Type: "telegram", Name: "telegram", Enabled: true})
+	}
+	if cfg.Alerts.Email.Host != "" && len(cfg.Alerts.Email.To) > 0 {
+		channels = append(channels, entities.AlertChannel{Type: "email", Name: "email", Enabled: true})
+	}
+	return channels
+}
+
+func eventLogType(t entities.EventType) string {
+	switch t {
+	case entities.EventDown, entities.EventSSLInvalid, entities.EventSSLRevoked:
+		return "error"
+	case entities.EventUp:
+		return "info"
+	default:
+		return "warning"
+	}
+}
+
 func configureSlackAttachment(message, logType string) slack.Attachment {
 	attachment := slack.Attachment{
 		Color: "#36a64f", // Default color
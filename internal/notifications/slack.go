@@ -1,11 +1,23 @@
 package notifications
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
 	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
 )
 
+// Action IDs dos botões interativos enviados nos alertas de incidente
+const (
+	SlackActionAcknowledge = "incident_ack"
+	SlackActionMute        = "incident_mute_1h"
+	SlackActionResolve     = "incident_resolve"
+)
+
 func SendSlackMsg(cfg *config.AppConfig, attachmentBody slack.Attachment) error {
 	client := slack.New(cfg.Alerts.Slack.Token)
 	attachment := attachmentBody
@@ -17,3 +29,109 @@ func SendSlackMsg(cfg *config.AppConfig, attachmentBody slack.Attachment) error
 	}
 	return nil
 }
+
+// SendSlackIncident envia o alerta de um incidente em Block Kit, com os botões de ação.
+// Quando text é vazio é usado o resumo padrão do incidente.
+func SendSlackIncident(cfg *config.AppConfig, endpoint *entities.Endpoint, incident *entities.Incident, text string) error {
+	client := slack.New(cfg.Alerts.Slack.Token)
+
+	fallback := text
+	if fallback == "" {
+		fallback = incidentSummary(endpoint, incident)
+	}
+	_, _, err := client.PostMessage(cfg.Alerts.Slack.Channel,
+		slack.MsgOptionText(fallback, false),
+		slack.MsgOptionBlocks(BuildIncidentBlocks(cfg, endpoint, incident, text)...),
+	)
+	if err != nil {
+		log.Error().Msgf("Failed to send message to Slack: %v", err)
+		return err
+	}
+	return nil
+}
+
+// BuildIncidentBlocks monta a mensagem Block Kit de um incidente. O texto renderizado pelo template
+// substitui o cabeçalho padrão e os botões só são incluídos enquanto o incidente não estiver resolvido.
+func BuildIncidentBlocks(cfg *config.AppConfig, endpoint *entities.Endpoint, incident *entities.Incident, text string) []slack.Block {
+	var header slack.Block = slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, incidentSummary(endpoint, incident), true, false))
+	if text != "" {
+		header = slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)
+	}
+
+	latency := "-"
+	if endpoint.ResponseTime > 0 {
+		latency = fmt.Sprintf("%d ms", endpoint.ResponseTime)
+	}
+	fields := []*slack.TextBlockObject{
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*Endpoint:*\n%s", endpoint.Name), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*Status:*\n%s %s", incidentEmoji(incident.Status), incident.Status), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*Latência:*\n%s", latency), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*Início:*\n<!date^%d^{date_short_pretty} {time}|%s>", incident.StartedAt.Unix(), incident.StartedAt.Format(time.RFC3339)), false, false),
+	}
+	blocks := []slack.Block{
+		header,
+		slack.NewSectionBlock(nil, fields, nil),
+	}
+
+	if incident.ErrorMessage != "" {
+		errText := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*Erro:*\n```%s```", incident.ErrorMessage), false, false)
+		blocks = append(blocks, slack.NewSectionBlock(errText, nil, nil))
+	}
+
+	links := []string{fmt.Sprintf("<%s|%s>", endpoint.URL(), endpoint.URL())}
+	if cfg.Server.PublicURL != "" {
+		details := fmt.Sprintf("%s/api/v1/endpoints/%s/status", strings.TrimRight(cfg.Server.PublicURL, "/"), endpoint.ID.Hex())
+		links = append(links, fmt.Sprintf("<%s|Detalhes>", details))
+	}
+	footer := []slack.MixedElement{
+		slack.NewTextBlockObject(slack.MarkdownType, strings.Join(links, " • "), false, false),
+	}
+	switch incident.Status {
+	case entities.IncidentAcknowledged:
+		footer = append(footer, slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("Reconhecido por *%s*", incident.AcknowledgedBy), false, false))
+	case entities.IncidentResolved:
+		footer = append(footer, slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("Resolvido por *%s*", incident.ResolvedBy), false, false))
+	}
+	if endpoint.IsMuted(time.Now()) {
+		footer = append(footer, slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("Silenciado até <!date^%d^{time}|%s>", endpoint.MutedUntil.Unix(), endpoint.MutedUntil.Format(time.RFC3339)), false, false))
+	}
+	blocks = append(blocks, slack.NewContextBlock("", footer...))
+
+	if incident.Status == entities.IncidentResolved {
+		return blocks
+	}
+
+	id := incident.ID.Hex()
+	var buttons []slack.BlockElement
+	if incident.Status == entities.IncidentOpen {
+		ack := slack.NewButtonBlockElement(SlackActionAcknowledge, id, slack.NewTextBlockObject(slack.PlainTextType, "Acknowledge", false, false))
+		buttons = append(buttons, ack.WithStyle(slack.StylePrimary))
+	}
+	mute := slack.NewButtonBlockElement(SlackActionMute, id, slack.NewTextBlockObject(slack.PlainTextType, "Mute 1h", false, false))
+	resolve := slack.NewButtonBlockElement(SlackActionResolve, id, slack.NewTextBlockObject(slack.PlainTextType, "Resolve", false, false))
+	buttons = append(buttons, mute, resolve.WithStyle(slack.StyleDanger))
+	blocks = append(blocks, slack.NewActionBlock("incident_actions", buttons...))
+
+	return blocks
+}
+
+// incidentSummary é o texto curto usado no header e como fallback das notificações
+func incidentSummary(endpoint *entities.Endpoint, incident *entities.Incident) string {
+	if incident.Status == entities.IncidentResolved {
+		return fmt.Sprintf("✅ %s está online novamente", endpoint.Name)
+	}
+	return fmt.Sprintf("🚨 %s está offline", endpoint.Name)
+}
+
+func incidentEmoji(status entities.IncidentStatus) string {
+	switch status {
+	case entities.IncidentOpen:
+		return "🔴"
+	case entities.IncidentAcknowledged:
+		return "🟡"
+	case entities.IncidentResolved:
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
@@ -1,28 +1,143 @@
 package notifications
 
 import (
+	"fmt"
+	"html"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog/log"
 )
 
+// Prefixos do callback data dos botões inline enviados nos alertas
+const (
+	TelegramActionAcknowledge = "ack"
+	TelegramActionMute        = "mute"
+	TelegramActionResolve     = "resolve"
+)
+
+var (
+	telegramMu     sync.Mutex
+	telegramClient *tgbotapi.BotAPI
+)
+
+// telegramAPI devolve o client do bot, criado uma única vez e reaproveitado entre mensagens
+func telegramAPI(cfg *config.AppConfig) (*tgbotapi.BotAPI, error) {
+	telegramMu.Lock()
+	defer telegramMu.Unlock()
+
+	if telegramClient != nil && telegramClient.Token == cfg.Alerts.Telegram.BotToken {
+		return telegramClient, nil
+	}
+
+	endpoint := tgbotapi.APIEndpoint
+	if cfg.Alerts.Telegram.APIURL != "" {
+		endpoint = strings.TrimRight(cfg.Alerts.Telegram.APIURL, "/") + "/bot%s/%s"
+	}
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.Alerts.Telegram.BotToken, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	telegramClient = bot
+	return telegramClient, nil
+}
+
 func SendTelegramMsg(cfg *config.AppConfig, message string) error {
-	bot, err := tgbotapi.NewBotAPI(cfg.Alerts.Telegram.BotToken)
+	msg, err := newTelegramMessage(cfg, message)
+	if err != nil {
+		return err
+	}
+	return sendTelegram(cfg, msg)
+}
+
+// SendTelegramIncident envia o alerta de um incidente com botões inline de Ack/Mute/Resolve.
+// Quando text é vazio é usado o formato padrão do incidente.
+func SendTelegramIncident(cfg *config.AppConfig, endpoint *entities.Endpoint, incident *entities.Incident, text string) error {
+	if text == "" {
+		text = FormatTelegramIncident(endpoint, incident)
+	}
+	msg, err := newTelegramMessage(cfg, text)
 	if err != nil {
-		log.Error().Msgf("Failed to create bot: %v", err)
 		return err
 	}
+	if keyboard, ok := TelegramIncidentKeyboard(endpoint, incident); ok {
+		msg.ReplyMarkup = keyboard
+	}
+	return sendTelegram(cfg, msg)
+}
+
+// FormatTelegramIncident monta o texto de um alerta de incidente, com *negrito* e `código` convertidos por telegramHTML
+func FormatTelegramIncident(endpoint *entities.Endpoint, incident *entities.Incident) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%s*\n", incidentSummary(endpoint, incident)))
+	b.WriteString(fmt.Sprintf("Status: %s %s\n", incidentEmoji(incident.Status), incident.Status))
+	b.WriteString(fmt.Sprintf("URL: %s\n", endpoint.URL()))
+	if endpoint.ResponseTime > 0 {
+		b.WriteString(fmt.Sprintf("Latência: %d ms\n", endpoint.ResponseTime))
+	}
+	if incident.ErrorMessage != "" {
+		b.WriteString(fmt.Sprintf("Erro: `%s`\n", incident.ErrorMessage))
+	}
+	b.WriteString(fmt.Sprintf("Incidente: `%s`", incident.ID.Hex()))
+	return b.String()
+}
+
+// TelegramIncidentKeyboard devolve os botões inline do incidente; incidentes resolvidos não têm botões
+func TelegramIncidentKeyboard(endpoint *entities.Endpoint, incident *entities.Incident) (tgbotapi.InlineKeyboardMarkup, bool) {
+	if incident.Status == entities.IncidentResolved {
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	var row []tgbotapi.InlineKeyboardButton
+	if incident.Status == entities.IncidentOpen {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("✅ Ack", TelegramActionAcknowledge+":"+incident.ID.Hex()))
+	}
+	row = append(row,
+		tgbotapi.NewInlineKeyboardButtonData("🔕 Mute 1h", TelegramActionMute+":"+endpoint.ID.Hex()),
+		tgbotapi.NewInlineKeyboardButtonData("✔️ Resolve", TelegramActionResolve+":"+incident.ID.Hex()),
+	)
+	return tgbotapi.NewInlineKeyboardMarkup(row), true
+}
+
+func newTelegramMessage(cfg *config.AppConfig, message string) (tgbotapi.MessageConfig, error) {
 	chatID, err := strconv.ParseInt(cfg.Alerts.Telegram.ChatID, 10, 64)
 	if err != nil {
 		log.Error().Msgf("Failed to parse chat ID: %v", err)
-		return err
+		return tgbotapi.MessageConfig{}, err
 	}
 
-	msg := tgbotapi.NewMessage(chatID, message)
-	msg.ParseMode = "Markdown"
+	msg := tgbotapi.NewMessage(chatID, telegramHTML(message))
+	msg.ParseMode = tgbotapi.ModeHTML
+	return msg, nil
+}
+
+// telegramMarkup encontra os trechos `código` e *negrito* usados nos templates e nos textos do bot
+var telegramMarkup = regexp.MustCompile("`([^`\n]+)`|\\*([^*\n]+)\\*")
+
+// telegramHTML escapa o texto (nomes de endpoints e mensagens de erro podem ter _, * ou [, que fazem o
+// Telegram recusar a mensagem em Markdown) e converte *negrito* e `código` para tags HTML
+func telegramHTML(text string) string {
+	return telegramMarkup.ReplaceAllStringFunc(html.EscapeString(text), func(m string) string {
+		if m[0] == '`' {
+			return "<code>" + m[1:len(m)-1] + "</code>"
+		}
+		return "<b>" + m[1:len(m)-1] + "</b>"
+	})
+}
+
+func sendTelegram(cfg *config.AppConfig, msg tgbotapi.Chattable) error {
+	bot, err := telegramAPI(cfg)
+	if err != nil {
+		log.Error().Msgf("Failed to create bot: %v", err)
+		return err
+	}
 
 	_, err = bot.Send(msg)
 	if err != nil {
@@ -0,0 +1,336 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/config"
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/monitors"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const telegramHelp = `Comandos disponíveis:
+/status - status de todos os endpoints
+/down - endpoints offline e incidentes abertos
+/ack <incidente> - reconhece um incidente
+/mute <endpoint> [duração] - silencia os alertas (padrão 1h)
+/check <endpoint> - executa um health check agora`
+
+// TelegramBot - Bot de longa duração que responde a comandos e aos botões dos alertas
+type TelegramBot struct {
+	cfg       *config.AppConfig
+	api       *tgbotapi.BotAPI
+	endpoints repositories.EndpointRepository
+	incidents repositories.IncidentRepository
+	allowed   map[int64]bool
+}
+
+func NewTelegramBot(cfg *config.AppConfig, endpoints repositories.EndpointRepository, incidents repositories.IncidentRepository) (*TelegramBot, error) {
+	api, err := telegramAPI(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[int64]bool)
+	for _, id := range cfg.Alerts.Telegram.AllowedChatIDs {
+		allowed[id] = true
+	}
+	// o chat que recebe os alertas é sempre autorizado
+	if chatID, err := strconv.ParseInt(cfg.Alerts.Telegram.ChatID, 10, 64); err == nil {
+		allowed[chatID] = true
+	}
+
+	return &TelegramBot{
+		cfg:       cfg,
+		api:       api,
+		endpoints: endpoints,
+		incidents: incidents,
+		allowed:   allowed,
+	}, nil
+}
+
+// Run processa as atualizações do bot até o contexto ser cancelado
+func (b *TelegramBot) Run(ctx context.Context) {
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 30
+	updates := b.api.GetUpdatesChan(u)
+	log.Info().Str("bot", b.api.Self.UserName).Msg("🤖 Bot do Telegram iniciado")
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.api.StopReceivingUpdates()
+			log.Info().Msg("🤖 Bot do Telegram finalizado")
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			b.handleUpdate(ctx, update)
+		}
+	}
+}
+
+func (b *TelegramBot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
+	switch {
+	case update.CallbackQuery != nil:
+		b.handleCallback(ctx, update.CallbackQuery)
+	case update.Message != nil && update.Message.IsCommand():
+		b.handleCommand(ctx, update.Message)
+	}
+}
+
+func (b *TelegramBot) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.allowed[msg.Chat.ID] {
+		log.Warn().Int64("chat_id", msg.Chat.ID).Str("command", msg.Command()).Msg("Comando do Telegram de chat não autorizado")
+		b.reply(msg.Chat.ID, "⛔ Chat não autorizado")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	args := strings.Fields(msg.CommandArguments())
+	user := telegramUser(msg.From)
+
+	var text string
+	var err error
+	switch msg.Command() {
+	case "status":
+		text, err = b.cmdStatus(ctx)
+	case "down":
+		text, err = b.cmdDown(ctx)
+	case "ack":
+		text, err = b.cmdAck(ctx, args, user)
+	case "mute":
+		text, err = b.cmdMute(ctx, args)
+	case "check":
+		text, err = b.cmdCheck(ctx, args)
+	default:
+		text = telegramHelp
+	}
+	if err != nil {
+		text = "❌ " + err.Error()
+	}
+	b.reply(msg.Chat.ID, text)
+}
+
+func (b *TelegramBot) cmdStatus(ctx context.Context) (string, error) {
+	endpoints, err := b.endpoints.FindAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(endpoints) == 0 {
+		return "Nenhum endpoint cadastrado", nil
+	}
+
+	var sb strings.Builder
+	for _, e := range endpoints {
+		sb.WriteString(fmt.Sprintf("%s *%s* - %s", statusEmoji(e.Status), e.Name, e.Status))
+		if e.ResponseTime > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d ms)", e.ResponseTime))
+		}
+		if e.IsMuted(time.Now()) {
+			sb.WriteString(" 🔕")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func (b *TelegramBot) cmdDown(ctx context.Context) (string, error) {
+	endpoints, err := b.endpoints.FindAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	incidents, err := b.incidents.FindOpen(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, e := range endpoints {
+		if e.Status != entities.StatusOffline {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("🔴 *%s*", e.Name))
+		if e.ErrorMessage != "" {
+			sb.WriteString(fmt.Sprintf(": `%s`", e.ErrorMessage))
+		}
+		sb.WriteString("\n")
+	}
+	for _, i := range incidents {
+		sb.WriteString(fmt.Sprintf("%s `%s` %s desde %s\n", incidentEmoji(i.Status), i.ID.Hex(), i.EndpointName, i.StartedAt.Format("02/01 15:04")))
+	}
+	if sb.Len() == 0 {
+		return "✅ Todos os endpoints estão online", nil
+	}
+	return sb.String(), nil
+}
+
+func (b *TelegramBot) cmdAck(ctx context.Context, args []string, user string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("uso: /ack <incidente>")
+	}
+	id, err := primitive.ObjectIDFromHex(args[0])
+	if err != nil {
+		return "", errors.New("ID de incidente inválido")
+	}
+	incident, err := b.incidents.Acknowledge(ctx, id, user)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s Incidente de *%s* está %s", incidentEmoji(incident.Status), incident.EndpointName, incident.Status), nil
+}
+
+func (b *TelegramBot) cmdMute(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("uso: /mute <endpoint> [duração]")
+	}
+	endpoint, err := b.findEndpoint(ctx, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	duration := time.Hour
+	if len(args) > 1 {
+		duration, err = time.ParseDuration(args[1])
+		if err != nil || duration <= 0 {
+			return "", errors.New("duração inválida, use por exemplo 30m ou 2h")
+		}
+	}
+
+	until := time.Now().Add(duration)
+	if err := b.endpoints.Mute(ctx, endpoint.ID, until); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🔕 *%s* silenciado até %s", endpoint.Name, until.Format("02/01 15:04")), nil
+}
+
+func (b *TelegramBot) cmdCheck(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("uso: /check <endpoint>")
+	}
+	endpoint, err := b.findEndpoint(ctx, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	result := monitors.CheckEndpoint(ctx, endpoint)
+	text := fmt.Sprintf("%s *%s* - %s (%d ms)", statusEmoji(result.Status), endpoint.Name, result.Status, result.ResponseTime.Milliseconds())
+	if result.ErrorMessage != "" {
+		text += fmt.Sprintf("\n`%s`", result.ErrorMessage)
+	}
+	return text, nil
+}
+
+// handleCallback trata os botões inline enviados junto com os alertas de incidente
+func (b *TelegramBot) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	if query.Message == nil || !b.allowed[query.Message.Chat.ID] {
+		b.answer(query.ID, "⛔ Chat não autorizado")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	action, value, _ := strings.Cut(query.Data, ":")
+	id, err := primitive.ObjectIDFromHex(value)
+	if err != nil {
+		b.answer(query.ID, "Ação inválida")
+		return
+	}
+
+	user := telegramUser(query.From)
+	var incident *entities.Incident
+	switch action {
+	case TelegramActionAcknowledge:
+		incident, err = b.incidents.Acknowledge(ctx, id, user)
+	case TelegramActionResolve:
+		incident, err = b.incidents.Resolve(ctx, id, user)
+	case TelegramActionMute:
+		err = b.endpoints.Mute(ctx, id, time.Now().Add(time.Hour))
+		if err == nil {
+			b.answer(query.ID, "🔕 Silenciado por 1h")
+			return
+		}
+	default:
+		b.answer(query.ID, "Ação desconhecida")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("action", action).Msg("Erro ao processar ação do Telegram")
+		b.answer(query.ID, "❌ "+err.Error())
+		return
+	}
+
+	b.answer(query.ID, fmt.Sprintf("Incidente %s", incident.Status))
+
+	// atualiza os botões da mensagem original, preservando o texto renderizado pelo template
+	keyboard, ok := TelegramIncidentKeyboard(&entities.Endpoint{ID: incident.EndpointID}, incident)
+	if !ok {
+		keyboard = tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, keyboard)
+	if _, err := b.api.Request(edit); err != nil {
+		log.Error().Msgf("Failed to update Telegram message: %v", err)
+	}
+}
+
+// findEndpoint aceita tanto o ID quanto o nome do endpoint
+func (b *TelegramBot) findEndpoint(ctx context.Context, ref string) (*entities.Endpoint, error) {
+	if id, err := primitive.ObjectIDFromHex(ref); err == nil {
+		return b.endpoints.FindByID(ctx, id)
+	}
+	endpoint, err := b.endpoints.FindByName(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %q não encontrado", ref)
+	}
+	return endpoint, nil
+}
+
+func (b *TelegramBot) reply(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, telegramHTML(text))
+	msg.ParseMode = tgbotapi.ModeHTML
+	if _, err := b.api.Send(msg); err != nil {
+		log.Error().Msgf("Failed to send message to Telegram: %v", err)
+	}
+}
+
+func (b *TelegramBot) answer(callbackID, text string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(callbackID, text)); err != nil {
+		log.Error().Msgf("Failed to answer Telegram callback: %v", err)
+	}
+}
+
+func telegramUser(u *tgbotapi.User) string {
+	if u == nil {
+		return "telegram"
+	}
+	if u.UserName != "" {
+		return "@" + u.UserName
+	}
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+func statusEmoji(status entities.EndpointStatus) string {
+	switch status {
+	case entities.StatusOnline:
+		return "🟢"
+	case entities.StatusOffline:
+		return "🔴"
+	case entities.StatusDegraded:
+		return "🟡"
+	default:
+		return "⚪"
+	}
+}
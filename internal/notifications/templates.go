@@ -0,0 +1,366 @@
+package notifications
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TemplateData é o modelo de dados disponível nos templates de notificação:
+//
+//	{{ .Event }}                 tipo do evento (down, up, ssl_expiring, flapping, reminder)
+//	{{ .Endpoint.Name }}         campos de TemplateEndpoint (Domain, Type, Status, SSLData.DaysLeft, DomainData, ...)
+//	{{ .URL }}                   URL verificada pelo health check
+//	{{ .Incident }}              incidente relacionado (nil para eventos sem incidente)
+//	{{ .Message }}               mensagem livre informada por quem disparou o evento
+//	{{ .Downtime }}              duração do incidente até o momento
+//	{{ .Now }}                   horário da renderização
+//
+// Funções auxiliares: duration, since, humanize, date, truncate, upper, lower, default, emoji.
+type TemplateData struct {
+	Event    entities.EventType
+	Endpoint TemplateEndpoint
+	URL      string
+	Incident *entities.Incident
+	Message  string
+	Downtime time.Duration
+	Now      time.Time
+}
+
+// TemplateEndpoint é a visão do endpoint exposta aos templates. Os templates são escritos pelos usuários e
+// o texto vai para Slack e Telegram, então a configuração e as credenciais do endpoint ficam de fora.
+type TemplateEndpoint struct {
+	ID           primitive.ObjectID
+	Name         string
+	Domain       string
+	URL          string
+	Type         entities.EndpointType
+	Status       entities.EndpointStatus
+	ResponseTime int // ms
+	ErrorMessage string
+	LastCheck    time.Time
+	MutedUntil   time.Time
+	SSLData      entities.SSLData
+	DomainData   entities.DomainData
+}
+
+func newTemplateEndpoint(e *entities.Endpoint) TemplateEndpoint {
+	return TemplateEndpoint{
+		ID:           e.ID,
+		Name:         e.Name,
+		Domain:       e.Domain,
+		URL:          e.URL(),
+		Type:         e.MonitorType(),
+		Status:       e.Status,
+		ResponseTime: e.ResponseTime,
+		ErrorMessage: e.ErrorMessage,
+		LastCheck:    e.LastCheck,
+		MutedUntil:   e.MutedUntil,
+		SSLData:      e.SSLData,
+		DomainData:   e.DomainData,
+	}
+}
+
+// DefaultTemplates são usados quando não há template cadastrado para o canal ou grupo
+var DefaultTemplates = map[entities.EventType]string{
+	entities.EventDown: `🚨 *{{ .Endpoint.Name }}* está offline
+URL: {{ .URL }}
+{{- if .Endpoint.ErrorMessage }}
+Erro: {{ .Endpoint.ErrorMessage | truncate 300 }}{{ end }}
+{{- if .Incident }}
+Desde: {{ humanize .Incident.StartedAt }}{{ end }}`,
+	entities.EventUp: `✅ *{{ .Endpoint.Name }}* está online novamente
+URL: {{ .URL }}
+{{- if .Downtime }}
+Indisponível por: {{ duration .Downtime }}{{ end }}`,
+	entities.EventSSLExpiring: `🔐 O certificado SSL de *{{ .Endpoint.Name }}* expira em {{ .Endpoint.SSLData.DaysLeft }} dia(s)
+Expiração: {{ date "02/01/2006" .Endpoint.SSLData.ExpirationDate }}`,
+	entities.EventFlapping: `⚠️ *{{ .Endpoint.Name }}* está oscilando entre online e offline
+URL: {{ .URL }}`,
+	entities.EventReminder: `⏰ *{{ .Endpoint.Name }}* continua offline
+{{- if .Downtime }} há {{ duration .Downtime }}{{ end }}
+{{- if .Incident }}
+Incidente: {{ .Incident.ID.Hex }} ({{ .Incident.Status }}){{ end }}`,
+	entities.EventSSLInvalid: `❌ O certificado SSL de *{{ .Endpoint.Name }}* é inválido
+{{ .Message }}`,
+	entities.EventSSLRevoked: `⛔ O certificado SSL de *{{ .Endpoint.Name }}* foi revogado
+{{ .Message }}`,
+	entities.EventSSLChanged: `🔁 O certificado SSL de *{{ .Endpoint.Name }}* mudou
+{{ .Message }}`,
+	entities.EventDomainExpiring: `📅 O domínio *{{ .Endpoint.DomainData.Domain }}* ({{ .Endpoint.Name }}) expira em {{ .Endpoint.DomainData.DaysLeft }} dia(s)
+Expiração: {{ date "02/01/2006" .Endpoint.DomainData.ExpirationDate }}
+{{- if .Endpoint.DomainData.Registrar }}
+Registrar: {{ .Endpoint.DomainData.Registrar }}{{ end }}
+{{- if .Message }}
+{{ .Message }}{{ end }}`,
+	entities.EventDNSChanged: `🔀 As respostas DNS de *{{ .Endpoint.Name }}* mudaram
+{{ .Message }}`,
+}
+
+// TemplateFuncs são as funções auxiliares disponíveis nos templates
+var TemplateFuncs = template.FuncMap{
+	"duration": humanDuration,
+	"since": func(t time.Time) string {
+		return humanDuration(time.Since(t))
+	},
+	"humanize": humanizeTime,
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"truncate": truncate,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"emoji": func(status entities.EndpointStatus) string {
+		return statusEmoji(status)
+	},
+}
+
+const (
+	// maxTemplateOutput limita o texto renderizado; nenhum canal aceita mensagens desse tamanho
+	maxTemplateOutput = 64 << 10
+	// templateTimeout limita a renderização para um template salvo pela API não travar o envio dos alertas
+	templateTimeout = 2 * time.Second
+)
+
+var (
+	errTemplateTooLarge = fmt.Errorf("template gerou mais de %d KB", maxTemplateOutput>>10)
+	errTemplateTimeout  = fmt.Errorf("template excedeu %s de renderização", templateTimeout)
+)
+
+// ParseTemplate valida e compila o corpo de um template
+func ParseTemplate(body string) (*template.Template, error) {
+	tmpl, err := template.New("notification").Funcs(TemplateFuncs).Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			if err := checkRanges(t.Tree.Root); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tmpl, nil
+}
+
+// checkRanges recusa range sobre um número: o loop roda sem produzir saída, então nem o limite de
+// tamanho nem o prazo da renderização o interrompem
+func checkRanges(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkRanges(child); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return errors.Join(checkRanges(n.List), checkRanges(n.ElseList))
+	case *parse.WithNode:
+		return errors.Join(checkRanges(n.List), checkRanges(n.ElseList))
+	case *parse.RangeNode:
+		for _, cmd := range n.Pipe.Cmds {
+			for _, arg := range cmd.Args {
+				if _, ok := arg.(*parse.NumberNode); ok {
+					return errors.New("range sobre número não é permitido em templates")
+				}
+			}
+		}
+		return errors.Join(checkRanges(n.List), checkRanges(n.ElseList))
+	}
+	return nil
+}
+
+// RenderTemplate renderiza o corpo do template com os dados informados, limitado a maxTemplateOutput
+// e a templateTimeout
+func RenderTemplate(body string, data TemplateData) (string, error) {
+	tmpl, err := ParseTemplate(body)
+	if err != nil {
+		return "", err
+	}
+
+	w := &templateWriter{deadline: time.Now().Add(templateTimeout)}
+	done := make(chan error, 1)
+	go func() { done <- tmpl.Execute(w, data) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return "", err
+		}
+	case <-time.After(templateTimeout):
+		// a goroutine para na próxima escrita, que falha depois do prazo
+		return "", errTemplateTimeout
+	}
+	return strings.TrimSpace(w.buf.String()), nil
+}
+
+// templateWriter interrompe a execução do template ao passar do tamanho máximo ou do prazo
+type templateWriter struct {
+	buf      bytes.Buffer
+	deadline time.Time
+}
+
+func (w *templateWriter) Write(p []byte) (int, error) {
+	if time.Now().After(w.deadline) {
+		return 0, errTemplateTimeout
+	}
+	if w.buf.Len()+len(p) > maxTemplateOutput {
+		return 0, errTemplateTooLarge
+	}
+	return w.buf.Write(p)
+}
+
+// NewTemplateData monta o modelo de dados de um evento
+func NewTemplateData(event Event) TemplateData {
+	now := time.Now()
+	data := TemplateData{
+		Event:    event.Type,
+		Incident: event.Incident,
+		Message:  event.Message,
+		Now:      now,
+	}
+	if event.Endpoint != nil {
+		data.Endpoint = newTemplateEndpoint(event.Endpoint)
+		data.URL = event.Endpoint.URL()
+	}
+	if event.Incident != nil {
+		end := now
+		if !event.Incident.ResolvedAt.IsZero() {
+			end = event.Incident.ResolvedAt
+		}
+		data.Downtime = end.Sub(event.Incident.StartedAt)
+	}
+	return data
+}
+
+// SampleTemplateData gera dados fictícios para o preview de templates
+func SampleTemplateData(eventType entities.EventType) TemplateData {
+	now := time.Now()
+	endpoint := &entities.Endpoint{
+		ID:           primitive.NewObjectID(),
+		Name:         "api-exemplo",
+		Domain:       "api.example.com",
+		Endpoint:     "/health",
+		Status:       entities.StatusOffline,
+		ResponseTime: 1250,
+		ErrorMessage: "status HTTP inesperado: 503",
+		CheckSSL:     true,
+		LastCheck:    now,
+	}
+	endpoint.SSLData.ExpirationDate = now.AddDate(0, 0, 14)
+	endpoint.SSLData.DaysLeft = 14
+	endpoint.SSLData.Issuer = "Let's Encrypt"
+
+	incident := &entities.Incident{
+		ID:           primitive.NewObjectID(),
+		EndpointID:   endpoint.ID,
+		EndpointName: endpoint.Name,
+		Status:       entities.IncidentOpen,
+		ErrorMessage: endpoint.ErrorMessage,
+		StartedAt:    now.Add(-17 * time.Minute),
+	}
+	if eventType == entities.EventUp {
+		endpoint.Status = entities.StatusOnline
+		endpoint.ErrorMessage = ""
+		incident.Status = entities.IncidentResolved
+		incident.ResolvedAt = now
+	}
+
+	return NewTemplateData(Event{
+		Type:     eventType,
+		Endpoint: endpoint,
+		Incident: incident,
+		Message:  "Mensagem de exemplo",
+	})
+}
+
+// humanDuration formata uma duração de forma legível, ex.: "2h 5m", "45s"
+func humanDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	d = d.Round(time.Second)
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 && days == 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds > 0 && days == 0 && hours == 0 {
+		parts = append(parts, fmt.Sprintf("%ds", seconds))
+	}
+	if len(parts) == 0 {
+		return "0s"
+	}
+	return strings.Join(parts, " ")
+}
+
+// humanizeTime descreve um horário relativo ao momento atual, ex.: "há 5 minutos", "em 3 dias"
+func humanizeTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	d := time.Since(t)
+	format := "há %s"
+	if d < 0 {
+		d = -d
+		format = "em %s"
+	}
+
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "agora"
+	case d < time.Hour:
+		unit = plural(int(d.Minutes()), "minuto", "minutos")
+	case d < 24*time.Hour:
+		unit = plural(int(d.Hours()), "hora", "horas")
+	default:
+		unit = plural(int(d.Hours()/24), "dia", "dias")
+	}
+	return fmt.Sprintf(format, unit)
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
+
+// truncate corta o texto em n caracteres, adicionando reticências
+func truncate(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n]) + "…"
+}
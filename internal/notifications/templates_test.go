@@ -0,0 +1,33 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+func TestRenderTemplateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"template padrão", DefaultTemplates[entities.EventDown], false},
+		{"range sobre número", `{{ range 1000000000 }}{{ end }}`, true},
+		{"range sobre número aninhado", `{{ if true }}{{ with 1 }}{{ range 10 }}x{{ end }}{{ end }}{{ end }}`, true},
+		{"range sobre número em define", `{{ define "x" }}{{ range 10 }}{{ end }}{{ end }}ok`, true},
+		{"saída no limite", `{{ .Message }}`, false},
+		{"saída acima do limite", `{{ .Message }}{{ .Message }}`, true},
+	}
+
+	data := SampleTemplateData(entities.EventDown)
+	data.Message = strings.Repeat("x", maxTemplateOutput/2+1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RenderTemplate(tt.body, data); (err != nil) != tt.wantErr {
+				t.Errorf("RenderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AlertChannelRepository interface {
+	Create(ctx context.Context, ch *entities.AlertChannel) (primitive.ObjectID, error)
+	FindAll(ctx context.Context) ([]entities.AlertChannel, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]entities.AlertChannel, error)
+	Update(ctx context.Context, ch *entities.AlertChannel) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type AlertGroupRepository interface {
+	Create(ctx context.Context, g *entities.AlertGroup) (primitive.ObjectID, error)
+	FindAll(ctx context.Context) ([]entities.AlertGroup, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]entities.AlertGroup, error)
+	Update(ctx context.Context, g *entities.AlertGroup) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type alertChannelRepository struct {
+	col *mongo.Collection
+}
+
+type alertGroupRepository struct {
+	col *mongo.Collection
+}
+
+func NewAlertChannelRepository(db *mongo.Database) AlertChannelRepository {
+	return &alertChannelRepository{
+		col: db.Collection("alert_channels"),
+	}
+}
+
+func NewAlertGroupRepository(db *mongo.Database) AlertGroupRepository {
+	return &alertGroupRepository{
+		col: db.Collection("alert_groups"),
+	}
+}
+
+func (r *alertChannelRepository) Create(ctx context.Context, ch *entities.AlertChannel) (primitive.ObjectID, error) {
+	if ch.ID.IsZero() {
+		ch.ID = primitive.NewObjectID()
+	}
+	if _, err := r.col.InsertOne(ctx, ch); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return ch.ID, nil
+}
+
+func (r *alertChannelRepository) FindAll(ctx context.Context) ([]entities.AlertChannel, error) {
+	return findAll[entities.AlertChannel](ctx, r.col, bson.M{})
+}
+
+func (r *alertChannelRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]entities.AlertChannel, error) {
+	return findAll[entities.AlertChannel](ctx, r.col, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (r *alertChannelRepository) Update(ctx context.Context, ch *entities.AlertChannel) error {
+	return replaceByID(ctx, r.col, ch.ID, ch)
+}
+
+func (r *alertChannelRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return deleteByID(ctx, r.col, id)
+}
+
+func (r *alertGroupRepository) Create(ctx context.Context, g *entities.AlertGroup) (primitive.ObjectID, error) {
+	if g.ID.IsZero() {
+		g.ID = primitive.NewObjectID()
+	}
+	if _, err := r.col.InsertOne(ctx, g); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return g.ID, nil
+}
+
+func (r *alertGroupRepository) FindAll(ctx context.Context) ([]entities.AlertGroup, error) {
+	return findAll[entities.AlertGroup](ctx, r.col, bson.M{})
+}
+
+func (r *alertGroupRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]entities.AlertGroup, error) {
+	return findAll[entities.AlertGroup](ctx, r.col, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (r *alertGroupRepository) Update(ctx context.Context, g *entities.AlertGroup) error {
+	return replaceByID(ctx, r.col, g.ID, g)
+}
+
+func (r *alertGroupRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return deleteByID(ctx, r.col, id)
+}
+
+// findAll executa o filtro e decodifica todos os documentos encontrados
+func findAll[T any](ctx context.Context, col *mongo.Collection, filter bson.M) ([]T, error) {
+	cursor, err := col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []T
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// replaceByID substitui o documento inteiro, devolvendo mongo.ErrNoDocuments se o ID não existir
+func replaceByID(ctx context.Context, col *mongo.Collection, id primitive.ObjectID, doc interface{}) error {
+	res, err := col.ReplaceOne(ctx, bson.M{"_id": id}, doc)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// deleteByID remove o documento, devolvendo mongo.ErrNoDocuments se o ID não existir
+func deleteByID(ctx context.Context, col *mongo.Collection, id primitive.ObjectID) error {
+	res, err := col.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DigestRepository interface {
+	Create(ctx context.Context, d *entities.Digest) (primitive.ObjectID, error)
+	FindAll(ctx context.Context) ([]entities.Digest, error)
+	FindEnabled(ctx context.Context) ([]entities.Digest, error)
+	Update(ctx context.Context, d *entities.Digest) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Claim marca o digest como enviado para o horário agendado; devolve false se outra réplica já o fez
+	Claim(ctx context.Context, id primitive.ObjectID, scheduled, now time.Time) (bool, error)
+	// Release desfaz o Claim feito em claimedAt quando o envio falha, devolvendo o último envio a previous
+	Release(ctx context.Context, id primitive.ObjectID, claimedAt, previous time.Time) error
+}
+
+type digestRepository struct {
+	col *mongo.Collection
+}
+
+func NewDigestRepository(db *mongo.Database) DigestRepository {
+	return &digestRepository{
+		col: db.Collection("digests"),
+	}
+}
+
+func (r *digestRepository) Create(ctx context.Context, d *entities.Digest) (primitive.ObjectID, error) {
+	now := time.Now().UTC()
+
+	if d.ID.IsZero() {
+		d.ID = primitive.NewObjectID()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	d.UpdatedAt = now
+
+	if _, err := r.col.InsertOne(ctx, d); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return d.ID, nil
+}
+
+func (r *digestRepository) FindAll(ctx context.Context) ([]entities.Digest, error) {
+	return findAll[entities.Digest](ctx, r.col, bson.M{})
+}
+
+func (r *digestRepository) FindEnabled(ctx context.Context) ([]entities.Digest, error) {
+	return findAll[entities.Digest](ctx, r.col, bson.M{"enabled": true})
+}
+
+// Update altera a configuração do digest preservando created_at e last_sent_at
+func (r *digestRepository) Update(ctx context.Context, d *entities.Digest) error {
+	d.UpdatedAt = time.Now().UTC()
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": d.ID}, bson.M{"$set": bson.M{
+		"name":        d.Name,
+		"frequency":   d.Frequency,
+		"time":        d.Time,
+		"weekday":     d.Weekday,
+		"timezone":    d.TimeZone,
+		"group_ids":   d.GroupIDs,
+		"channel_ids": d.ChannelIDs,
+		"enabled":     d.Enabled,
+		"updated_at":  d.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *digestRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return deleteByID(ctx, r.col, id)
+}
+
+func (r *digestRepository) Claim(ctx context.Context, id primitive.ObjectID, scheduled, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"last_sent_at": bson.M{"$exists": false}},
+			bson.M{"last_sent_at": bson.M{"$lt": scheduled.UTC()}},
+		},
+	}
+	res, err := r.col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_sent_at": now.UTC()}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *digestRepository) Release(ctx context.Context, id primitive.ObjectID, claimedAt, previous time.Time) error {
+	update := bson.M{"$unset": bson.M{"last_sent_at": ""}}
+	if !previous.IsZero() {
+		update = bson.M{"$set": bson.M{"last_sent_at": previous.UTC()}}
+	}
+	// o BSON guarda datas com precisão de milissegundos
+	filter := bson.M{"_id": id, "last_sent_at": claimedAt.UTC().Truncate(time.Millisecond)}
+	_, err := r.col.UpdateOne(ctx, filter, update)
+	return err
+}
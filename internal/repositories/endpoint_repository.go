@@ -13,6 +13,17 @@ import (
 type EndpointRepository interface {
 	Create(ctx context.Context, e *entities.Endpoint) (primitive.ObjectID, error)
 	FindAll(ctx context.Context) ([]entities.Endpoint, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Endpoint, error)
+	FindByName(ctx context.Context, name string) (*entities.Endpoint, error)
+	FindEnabled(ctx context.Context) ([]entities.Endpoint, error)
+	Update(ctx context.Context, e *entities.Endpoint) error
+	Mute(ctx context.Context, id primitive.ObjectID, until time.Time) error
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, status entities.EndpointStatus, responseTime int, errorMessage string, checkedAt time.Time) error
+	UpdateDNSAnswers(ctx context.Context, id primitive.ObjectID, answers []string) error
+	UpdateSSLData(ctx context.Context, id primitive.ObjectID, data entities.SSLData) error
+	AppendSSLHistory(ctx context.Context, id primitive.ObjectID, record entities.CertificateRecord) error
+	UpdateDomainData(ctx context.Context, id primitive.ObjectID, data entities.DomainData) error
+	RecordPing(ctx context.Context, token string, kind string, exitCode int, message string, at time.Time) error
 }
 
 type endpointRepository struct {
@@ -56,3 +67,132 @@ func (r *endpointRepository) FindAll(ctx context.Context) ([]entities.Endpoint,
 	}
 	return endpoints, nil
 }
+
+func (r *endpointRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Endpoint, error) {
+	var e entities.Endpoint
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Update substitui a configuração do endpoint sem tocar no status, nos pings e nas datas de check
+func (r *endpointRepository) Update(ctx context.Context, e *entities.Endpoint) error {
+	e.UpdatedAt = time.Now().UTC()
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": e.ID}, bson.M{"$set": endpointConfigFields(e)})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// endpointConfigFields são os campos substituídos pelo Update: toda a configuração do endpoint, incluindo
+// a configuração específica de cada tipo de monitor
+func endpointConfigFields(e *entities.Endpoint) bson.M {
+	return bson.M{
+		"name":            e.Name,
+		"domain":          e.Domain,
+		"type":            e.Type,
+		"port":            e.Port,
+		"endpoint":        e.Endpoint,
+		"timeout":         e.Timeout,
+		"interval":        e.Interval,
+		"assertions":      e.Assertions,
+		"tcp":             e.TCP,
+		"dns":             e.DNS,
+		"grpc":            e.GRPC,
+		"websocket":       e.WebSocket,
+		"mail":            e.Mail,
+		"database":        e.Database,
+		"exec":            e.Exec,
+		"starlark":        e.Starlark,
+		"heartbeat":       e.Heartbeat,
+		"scenario":        e.Scenario,
+		"check_ssl":       e.CheckSSL,
+		"ssl":             e.SSL,
+		"check_domain":    e.CheckDomain,
+		"domain_check":    e.DomainCheck,
+		"alert_group_ids": e.AlertGroupIDs,
+		"authentication":  e.Authentication,
+		"enabled":         e.Enabled,
+		"updated_at":      e.UpdatedAt,
+	}
+}
+
+func (r *endpointRepository) Mute(ctx context.Context, id primitive.ObjectID, until time.Time) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"muted_until": until.UTC(),
+		"updated_at":  time.Now().UTC(),
+	}})
+	return err
+}
+
+func (r *endpointRepository) FindByName(ctx context.Context, name string) (*entities.Endpoint, error) {
+	var e entities.Endpoint
+	if err := r.col.FindOne(ctx, bson.M{"name": name}).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *endpointRepository) FindEnabled(ctx context.Context) ([]entities.Endpoint, error) {
+	return findAll[entities.Endpoint](ctx, r.col, bson.M{"enabled": true})
+}
+
+// UpdateStatus grava o resultado do último check; responseTime em milissegundos
+func (r *endpointRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status entities.EndpointStatus, responseTime int, errorMessage string, checkedAt time.Time) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":        status,
+		"response_time": responseTime,
+		"error_message": errorMessage,
+		"last_check":    checkedAt.UTC(),
+	}})
+	return err
+}
+
+func (r *endpointRepository) UpdateDNSAnswers(ctx context.Context, id primitive.ObjectID, answers []string) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"dns_answers": answers}})
+	return err
+}
+
+func (r *endpointRepository) UpdateSSLData(ctx context.Context, id primitive.ObjectID, data entities.SSLData) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"ssl_data": data}})
+	return err
+}
+
+func (r *endpointRepository) UpdateDomainData(ctx context.Context, id primitive.ObjectID, data entities.DomainData) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"domain_data": data}})
+	return err
+}
+
+// AppendSSLHistory adiciona um certificado ao histórico, mantendo os MaxSSLHistory mais recentes
+func (r *endpointRepository) AppendSSLHistory(ctx context.Context, id primitive.ObjectID, record entities.CertificateRecord) error {
+	update := bson.M{"$push": bson.M{"ssl_history": bson.M{
+		"$each":  []entities.CertificateRecord{record},
+		"$slice": -entities.MaxSSLHistory,
+	}}}
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// RecordPing registra um ping de heartbeat (success, start ou fail) pelo token da URL
+func (r *endpointRepository) RecordPing(ctx context.Context, token string, kind string, exitCode int, message string, at time.Time) error {
+	set := bson.M{"pings.last_" + kind: at.UTC()}
+	if kind != "start" {
+		set["pings.exit_code"] = exitCode
+		set["pings.message"] = message
+	}
+
+	filter := bson.M{"type": entities.TypeHeartbeat, "heartbeat.token": token}
+	res, err := r.col.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+// TestEndpointConfigFieldsCoverChecks garante que toda configuração de tipo de monitor (*XxxCheck) é
+// substituída pelo Update; um campo esquecido faria o update ser ignorado silenciosamente
+func TestEndpointConfigFieldsCoverChecks(t *testing.T) {
+	fields := endpointConfigFields(&entities.Endpoint{})
+
+	typ := reflect.TypeOf(entities.Endpoint{})
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Type.Kind() != reflect.Ptr || f.Type.Elem().Kind() != reflect.Struct || !strings.HasSuffix(f.Type.Elem().Name(), "Check") {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("bson"), ",")
+		if _, ok := fields[name]; !ok {
+			t.Errorf("endpointConfigFields não inclui %q (campo %s)", name, f.Name)
+		}
+	}
+}
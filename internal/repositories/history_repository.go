@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HistoryRepository interface {
+	Create(ctx context.Context, h *entities.EndpointHealthHistory) error
+	FindByEndpoint(ctx context.Context, endpointID primitive.ObjectID, limit int64) ([]entities.EndpointHealthHistory, error)
+	Stats(ctx context.Context, from, to time.Time) ([]entities.EndpointStats, error)
+}
+
+type historyRepository struct {
+	col *mongo.Collection
+}
+
+func NewHistoryRepository(db *mongo.Database) HistoryRepository {
+	return &historyRepository{
+		col: db.Collection("endpoint_history"),
+	}
+}
+
+func (r *historyRepository) Create(ctx context.Context, h *entities.EndpointHealthHistory) error {
+	if h.ID.IsZero() {
+		h.ID = primitive.NewObjectID()
+	}
+	if h.CheckedAt.IsZero() {
+		h.CheckedAt = time.Now().UTC()
+	}
+	_, err := r.col.InsertOne(ctx, h)
+	return err
+}
+
+// FindByEndpoint devolve os checks mais recentes do endpoint
+func (r *historyRepository) FindByEndpoint(ctx context.Context, endpointID primitive.ObjectID, limit int64) ([]entities.EndpointHealthHistory, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "checked_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.col.Find(ctx, bson.M{"endpoint_id": endpointID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var history []entities.EndpointHealthHistory
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Stats agrega uptime e latência média por endpoint no período [from, to)
+func (r *historyRepository) Stats(ctx context.Context, from, to time.Time) ([]entities.EndpointStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"checked_at": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$endpoint_id",
+			"total_checks": bson.M{"$sum": 1},
+			"successful_checks": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", entities.StatusOnline}}, 1, 0},
+			}},
+			"avg_response_time": bson.M{"$avg": "$response_time"},
+		}}},
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// $avg devolve double, convertido para time.Duration abaixo
+	var stats []struct {
+		EndpointID       primitive.ObjectID `bson:"_id"`
+		TotalChecks      int                `bson:"total_checks"`
+		SuccessfulChecks int                `bson:"successful_checks"`
+		AvgResponseTime  float64            `bson:"avg_response_time"`
+	}
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.EndpointStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, entities.EndpointStats{
+			EndpointID:       s.EndpointID,
+			TotalChecks:      s.TotalChecks,
+			SuccessfulChecks: s.SuccessfulChecks,
+			AvgResponseTime:  time.Duration(s.AvgResponseTime),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IncidentRepository interface {
+	Create(ctx context.Context, i *entities.Incident) (primitive.ObjectID, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Incident, error)
+	FindOpen(ctx context.Context) ([]entities.Incident, error)
+	FindOpenByEndpoint(ctx context.Context, endpointID primitive.ObjectID) (*entities.Incident, error)
+	FindStartedBetween(ctx context.Context, from, to time.Time) ([]entities.Incident, error)
+	Acknowledge(ctx context.Context, id primitive.ObjectID, by string) (*entities.Incident, error)
+	Resolve(ctx context.Context, id primitive.ObjectID, by string) (*entities.Incident, error)
+}
+
+type incidentRepository struct {
+	col *mongo.Collection
+}
+
+func NewIncidentRepository(db *mongo.Database) IncidentRepository {
+	return &incidentRepository{
+		col: db.Collection("incidents"),
+	}
+}
+
+func (r *incidentRepository) Create(ctx context.Context, i *entities.Incident) (primitive.ObjectID, error) {
+	now := time.Now().UTC()
+
+	if i.ID.IsZero() {
+		i.ID = primitive.NewObjectID()
+	}
+	if i.StartedAt.IsZero() {
+		i.StartedAt = now
+	}
+	if i.Status == "" {
+		i.Status = entities.IncidentOpen
+	}
+	i.UpdatedAt = now
+
+	_, err := r.col.InsertOne(ctx, i)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return i.ID, nil
+}
+
+func (r *incidentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Incident, error) {
+	var i entities.Incident
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *incidentRepository) FindOpen(ctx context.Context) ([]entities.Incident, error) {
+	filter := bson.M{"status": bson.M{"$ne": entities.IncidentResolved}}
+	cursor, err := r.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var incidents []entities.Incident
+	if err := cursor.All(ctx, &incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// FindOpenByEndpoint devolve o incidente não resolvido do endpoint (mongo.ErrNoDocuments se não houver)
+func (r *incidentRepository) FindOpenByEndpoint(ctx context.Context, endpointID primitive.ObjectID) (*entities.Incident, error) {
+	var i entities.Incident
+	filter := bson.M{"endpoint_id": endpointID, "status": bson.M{"$ne": entities.IncidentResolved}}
+	if err := r.col.FindOne(ctx, filter).Decode(&i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *incidentRepository) FindStartedBetween(ctx context.Context, from, to time.Time) ([]entities.Incident, error) {
+	return findAll[entities.Incident](ctx, r.col, bson.M{"started_at": bson.M{"$gte": from, "$lt": to}})
+}
+
+// Acknowledge marca o incidente como reconhecido. Incidentes já resolvidos não são alterados.
+func (r *incidentRepository) Acknowledge(ctx context.Context, id primitive.ObjectID, by string) (*entities.Incident, error) {
+	now := time.Now().UTC()
+	filter := bson.M{"_id": id, "status": entities.IncidentOpen}
+	update := bson.M{"$set": bson.M{
+		"status":          entities.IncidentAcknowledged,
+		"acknowledged_by": by,
+		"acknowledged_at": now,
+		"updated_at":      now,
+	}}
+	return r.updateOne(ctx, id, filter, update)
+}
+
+// Resolve encerra o incidente manualmente
+func (r *incidentRepository) Resolve(ctx context.Context, id primitive.ObjectID, by string) (*entities.Incident, error) {
+	now := time.Now().UTC()
+	filter := bson.M{"_id": id, "status": bson.M{"$ne": entities.IncidentResolved}}
+	update := bson.M{"$set": bson.M{
+		"status":      entities.IncidentResolved,
+		"resolved_by": by,
+		"resolved_at": now,
+		"updated_at":  now,
+	}}
+	return r.updateOne(ctx, id, filter, update)
+}
+
+// updateOne aplica o update e devolve o estado atual do incidente, mesmo quando o filtro não casou
+func (r *incidentRepository) updateOne(ctx context.Context, id primitive.ObjectID, filter, update bson.M) (*entities.Incident, error) {
+	if _, err := r.col.UpdateOne(ctx, filter, update); err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TemplateRepository interface {
+	Create(ctx context.Context, t *entities.NotificationTemplate) (primitive.ObjectID, error)
+	FindAll(ctx context.Context) ([]entities.NotificationTemplate, error)
+	Update(ctx context.Context, t *entities.NotificationTemplate) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Resolve busca o template do canal para o evento e, se não houver, o primeiro template dos grupos
+	Resolve(ctx context.Context, channelID primitive.ObjectID, groupIDs []primitive.ObjectID, event entities.EventType) (*entities.NotificationTemplate, error)
+}
+
+type templateRepository struct {
+	col *mongo.Collection
+}
+
+func NewTemplateRepository(db *mongo.Database) TemplateRepository {
+	return &templateRepository{
+		col: db.Collection("notification_templates"),
+	}
+}
+
+func (r *templateRepository) Create(ctx context.Context, t *entities.NotificationTemplate) (primitive.ObjectID, error) {
+	now := time.Now().UTC()
+
+	if t.ID.IsZero() {
+		t.ID = primitive.NewObjectID()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	t.UpdatedAt = now
+
+	_, err := r.col.InsertOne(ctx, t)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return t.ID, nil
+}
+
+func (r *templateRepository) FindAll(ctx context.Context) ([]entities.NotificationTemplate, error) {
+	return findAll[entities.NotificationTemplate](ctx, r.col, bson.M{})
+}
+
+func (r *templateRepository) Update(ctx context.Context, t *entities.NotificationTemplate) error {
+	t.UpdatedAt = time.Now().UTC()
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": t.ID}, bson.M{"$set": bson.M{
+		"event_type": t.EventType,
+		"body":       t.Body,
+		"updated_at": t.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return deleteByID(ctx, r.col, id)
+}
+
+func (r *templateRepository) Resolve(ctx context.Context, channelID primitive.ObjectID, groupIDs []primitive.ObjectID, event entities.EventType) (*entities.NotificationTemplate, error) {
+	var t entities.NotificationTemplate
+	err := r.col.FindOne(ctx, bson.M{"channel_id": channelID, "event_type": event}).Decode(&t)
+	if err == nil {
+		return &t, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) || len(groupIDs) == 0 {
+		return nil, err
+	}
+
+	err = r.col.FindOne(ctx, bson.M{"group_id": bson.M{"$in": groupIDs}, "event_type": event}).Decode(&t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
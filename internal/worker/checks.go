@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/monitors"
+	"github.com/brunohfonseca/ratatoskr/internal/notifications"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// defaultInterval é usado quando o endpoint não define interval (segundos)
+	defaultInterval = 5 * time.Minute
+	// heartbeatInterval é a frequência em que os pings de monitores heartbeat são avaliados
+	heartbeatInterval = time.Minute
+	// maxConcurrentChecks limita quantos checks rodam em paralelo por execução
+	maxConcurrentChecks = 10
+	checkLockPrefix     = "ratatoskr:checks:lock:"
+)
+
+// CheckRunner executa os health checks vencidos, grava o histórico e abre/resolve incidentes
+type CheckRunner struct {
+	endpoints  repositories.EndpointRepository
+	history    repositories.HistoryRepository
+	incidents  repositories.IncidentRepository
+	dispatcher *notifications.Dispatcher
+	rdb        *redis.Client
+}
+
+func NewCheckRunner(endpoints repositories.EndpointRepository, history repositories.HistoryRepository, incidents repositories.IncidentRepository, dispatcher *notifications.Dispatcher, rdb *redis.Client) *CheckRunner {
+	return &CheckRunner{
+		endpoints:  endpoints,
+		history:    history,
+		incidents:  incidents,
+		dispatcher: dispatcher,
+		rdb:        rdb,
+	}
+}
+
+// RunDue verifica os endpoints habilitados cujo intervalo já passou desde o último check
+func (r *CheckRunner) RunDue(ctx context.Context) error {
+	endpoints, err := r.endpoints.FindEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		interval := checkInterval(&e)
+		if !e.LastCheck.IsZero() && now.Sub(e.LastCheck) < interval {
+			continue
+		}
+		// exec é opcional por worker: quem não tem a allowlist deixa o endpoint para um worker que tenha
+		if e.MonitorType() == entities.TypeExec && !monitors.ExecEnabled() {
+			continue
+		}
+		if !acquireLock(ctx, r.rdb, checkLockPrefix+e.ID.Hex(), interval) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(e entities.Endpoint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.check(ctx, &e)
+		}(e)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (r *CheckRunner) check(ctx context.Context, e *entities.Endpoint) {
+	result := monitors.CheckEndpoint(ctx, e)
+
+	err := r.history.Create(ctx, &entities.EndpointHealthHistory{
+		EndPointID:   e.ID,
+		Status:       result.Status,
+		ResponseTime: result.ResponseTime,
+		ErrorMessage: result.ErrorMessage,
+		Metrics:      result.Metrics,
+		Assertions:   result.Assertions,
+		Steps:        result.Steps,
+		Timings:      result.Timings,
+		GRPCCode:     result.GRPCCode,
+		Output:       result.Output,
+		CheckedAt:    result.CheckedAt,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to save check history")
+	}
+
+	previous := e.Status
+	responseTime := int(result.ResponseTime.Milliseconds())
+	if err := r.endpoints.UpdateStatus(ctx, e.ID, result.Status, responseTime, result.ErrorMessage, result.CheckedAt); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to update endpoint status")
+		return
+	}
+	e.Status = result.Status
+	e.ResponseTime = responseTime
+	e.ErrorMessage = result.ErrorMessage
+	e.LastCheck = result.CheckedAt
+
+	if result.Answers != nil {
+		r.trackDNSAnswers(ctx, e, result.Answers)
+	}
+
+	// qualquer status diferente de offline encerra o incidente: um plugin exec pode voltar de CRITICAL
+	// para WARNING (degraded) antes de OK
+	switch {
+	case result.Status == entities.StatusOffline && previous != entities.StatusOffline:
+		r.openIncident(ctx, e, result)
+	case result.Status != entities.StatusOffline && previous != entities.StatusOnline && previous != result.Status:
+		r.resolveIncident(ctx, e, previous)
+	}
+}
+
+// openIncident registra o incidente (se ainda não houver um aberto) e notifica a queda
+func (r *CheckRunner) openIncident(ctx context.Context, e *entities.Endpoint, result monitors.CheckResult) {
+	incident, err := r.incidents.FindOpenByEndpoint(ctx, e.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		incident = &entities.Incident{
+			EndpointID:   e.ID,
+			EndpointName: e.Name,
+			ErrorMessage: result.ErrorMessage,
+			StartedAt:    result.CheckedAt,
+		}
+		_, err = r.incidents.Create(ctx, incident)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to open incident")
+		return
+	}
+
+	log.Warn().Str("endpoint", e.Name).Str("error", result.ErrorMessage).Msg("🔴 Endpoint offline")
+	event := notifications.Event{Type: entities.EventDown, Endpoint: e, Incident: incident, Message: result.ErrorMessage}
+	if err := r.dispatcher.Dispatch(ctx, event); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to dispatch down event")
+	}
+}
+
+// resolveIncident fecha automaticamente o incidente aberto e notifica a recuperação. Vindo de um status
+// que não é offline (ex.: degraded -> online) só notifica se havia um incidente aberto.
+func (r *CheckRunner) resolveIncident(ctx context.Context, e *entities.Endpoint, previous entities.EndpointStatus) {
+	incident, err := r.incidents.FindOpenByEndpoint(ctx, e.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) && previous != entities.StatusOffline {
+		return
+	}
+	if err == nil {
+		incident, err = r.incidents.Resolve(ctx, incident.ID, "ratatoskr")
+	}
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to resolve incident")
+	}
+
+	log.Info().Str("endpoint", e.Name).Msg("🟢 Endpoint online")
+	event := notifications.Event{Type: entities.EventUp, Endpoint: e, Incident: incident}
+	if err := r.dispatcher.Dispatch(ctx, event); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to dispatch up event")
+	}
+}
+
+// trackDNSAnswers grava as respostas dns e, se configurado, notifica quando o conjunto muda
+func (r *CheckRunner) trackDNSAnswers(ctx context.Context, e *entities.Endpoint, answers []string) {
+	previous := e.DNSAnswers
+	if slices.Equal(previous, answers) {
+		return
+	}
+	if err := r.endpoints.UpdateDNSAnswers(ctx, e.ID, answers); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to update dns answers")
+		return
+	}
+	e.DNSAnswers = answers
+
+	if len(previous) == 0 || e.DNS == nil || !e.DNS.AlertOnChange {
+		return
+	}
+	message := fmt.Sprintf("Antes: %s\nAgora: %s", strings.Join(previous, ", "), strings.Join(answers, ", "))
+	log.Warn().Str("endpoint", e.Name).Strs("answers", answers).Msg("🔀 DNS answers changed")
+	if err := r.dispatcher.Dispatch(ctx, notifications.Event{Type: entities.EventDNSChanged, Endpoint: e, Message: message}); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to dispatch dns change event")
+	}
+}
+
+// acquireLock evita que outra instância do worker execute a mesma verificação no mesmo intervalo
+func acquireLock(ctx context.Context, rdb *redis.Client, key string, ttl time.Duration) bool {
+	locked, err := rdb.SetNX(ctx, key, time.Now().Unix(), ttl).Result()
+	return err == nil && locked
+}
+
+func checkInterval(e *entities.Endpoint) time.Duration {
+	if e.MonitorType() == entities.TypeHeartbeat {
+		return heartbeatInterval
+	}
+	if e.Interval > 0 {
+		return time.Duration(e.Interval) * time.Second
+	}
+	return defaultInterval
+}
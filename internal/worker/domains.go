@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/monitors"
+	"github.com/brunohfonseca/ratatoskr/internal/notifications"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const domainLockPrefix = "ratatoskr:domain:lock:"
+
+// DomainRunner consulta a expiração do registro dos domínios com CheckDomain no intervalo próprio de cada um
+type DomainRunner struct {
+	endpoints  repositories.EndpointRepository
+	rdap       *monitors.RDAPClient
+	dispatcher *notifications.Dispatcher
+	rdb        *redis.Client
+}
+
+func NewDomainRunner(endpoints repositories.EndpointRepository, rdap *monitors.RDAPClient, dispatcher *notifications.Dispatcher, rdb *redis.Client) *DomainRunner {
+	return &DomainRunner{endpoints: endpoints, rdap: rdap, dispatcher: dispatcher, rdb: rdb}
+}
+
+// RunDue consulta os domínios cujo intervalo já passou desde a última verificação
+func (r *DomainRunner) RunDue(ctx context.Context) error {
+	endpoints, err := r.endpoints.FindEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		if !e.CheckDomain {
+			continue
+		}
+		interval := e.DomainCheck.CheckInterval()
+		if !e.DomainData.CheckedAt.IsZero() && now.Sub(e.DomainData.CheckedAt) < interval {
+			continue
+		}
+		if !acquireLock(ctx, r.rdb, domainLockPrefix+e.ID.Hex(), interval) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(e entities.Endpoint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.check(ctx, &e)
+		}(e)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (r *DomainRunner) check(ctx context.Context, e *entities.Endpoint) {
+	previous := e.DomainData
+
+	report, err := r.rdap.FetchDomain(ctx, e.Domain, e.DomainCheck)
+	if err != nil {
+		log.Warn().Err(err).Str("endpoint", e.Name).Msg("Failed to fetch domain registration")
+		return
+	}
+
+	var notify bool
+	report.AlertedThreshold, notify = domainAlert(e.DomainCheck, previous.AlertedThreshold, report)
+
+	if err := r.endpoints.UpdateDomainData(ctx, e.ID, *report); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to update domain data")
+		return
+	}
+	e.DomainData = *report
+
+	if !notify {
+		return
+	}
+	log.Warn().Str("endpoint", e.Name).Str("domain", report.Domain).Int("days_left", report.DaysLeft).Msg("📅 Domain expiry alert")
+	event := notifications.Event{Type: entities.EventDomainExpiring, Endpoint: e, Message: strings.Join(report.Problems, "\n")}
+	if err := r.dispatcher.Dispatch(ctx, event); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to dispatch domain event")
+	}
+}
+
+// domainAlert aplica o mesmo controle do SSL, um alerta por limite até o domínio ser renovado, e devolve
+// o limite a registrar e se o alerta deve ser enviado. Sem data de expiração no RDAP não há dias
+// restantes para comparar, então o limite já notificado é mantido.
+func domainAlert(cfg *entities.DomainCheck, alerted int, report *entities.DomainData) (int, bool) {
+	if report.ExpirationDate.IsZero() {
+		return alerted, false
+	}
+	crossed := cfg.CrossedThreshold(report.DaysLeft)
+	notify := crossed > 0 && (alerted == 0 || crossed < alerted)
+	if crossed == 0 || notify {
+		alerted = crossed
+	}
+	return alerted, notify
+}
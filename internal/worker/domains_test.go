@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+func TestDomainAlert(t *testing.T) {
+	expiration := time.Now().AddDate(0, 0, 10)
+
+	tests := []struct {
+		name        string
+		alerted     int
+		report      entities.DomainData
+		wantAlerted int
+		wantNotify  bool
+	}{
+		{"sem data de expiração", 0, entities.DomainData{}, 0, false},
+		{"sem data de expiração mantém o limite notificado", 14, entities.DomainData{}, 14, false},
+		{"primeiro limite atingido", 0, entities.DomainData{ExpirationDate: expiration, DaysLeft: 10}, 14, true},
+		{"mesmo limite já notificado", 14, entities.DomainData{ExpirationDate: expiration, DaysLeft: 9}, 14, false},
+		{"limite menor atingido", 14, entities.DomainData{ExpirationDate: expiration, DaysLeft: 6}, 7, true},
+		{"renovado", 7, entities.DomainData{ExpirationDate: expiration, DaysLeft: 365}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerted, notify := domainAlert(nil, tt.alerted, &tt.report)
+			if alerted != tt.wantAlerted || notify != tt.wantNotify {
+				t.Errorf("domainAlert() = %d, %v; want %d, %v", alerted, notify, tt.wantAlerted, tt.wantNotify)
+			}
+		})
+	}
+}
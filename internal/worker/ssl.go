@@ -0,0 +1,174 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+	"github.com/brunohfonseca/ratatoskr/internal/monitors"
+	"github.com/brunohfonseca/ratatoskr/internal/notifications"
+	"github.com/brunohfonseca/ratatoskr/internal/repositories"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const sslLockPrefix = "ratatoskr:ssl:lock:"
+
+// SSLRunner verifica os certificados dos endpoints com CheckSSL no intervalo próprio de cada um
+type SSLRunner struct {
+	endpoints  repositories.EndpointRepository
+	dispatcher *notifications.Dispatcher
+	rdb        *redis.Client
+}
+
+func NewSSLRunner(endpoints repositories.EndpointRepository, dispatcher *notifications.Dispatcher, rdb *redis.Client) *SSLRunner {
+	return &SSLRunner{endpoints: endpoints, dispatcher: dispatcher, rdb: rdb}
+}
+
+// RunDue verifica os certificados cujo intervalo já passou desde a última verificação
+func (r *SSLRunner) RunDue(ctx context.Context) error {
+	endpoints, err := r.endpoints.FindEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		if !e.CheckSSL || e.MonitorType() == entities.TypeHeartbeat {
+			continue
+		}
+		interval := e.SSL.CheckInterval()
+		if !e.SSLData.CheckedAt.IsZero() && now.Sub(e.SSLData.CheckedAt) < interval {
+			continue
+		}
+		if !acquireLock(ctx, r.rdb, sslLockPrefix+e.ID.Hex(), interval) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(e entities.Endpoint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.check(ctx, &e)
+		}(e)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (r *SSLRunner) check(ctx context.Context, e *entities.Endpoint) {
+	previous := e.SSLData
+
+	report, err := monitors.FetchSSL(ctx, e.Domain, monitors.SSLPort(e), e.SSL)
+	if err != nil {
+		// sem conexão não há certificado para avaliar; a indisponibilidade é alertada pelo health check
+		log.Warn().Err(err).Str("endpoint", e.Name).Msg("Failed to fetch SSL certificate")
+		return
+	}
+
+	// o limite notificado vale até o certificado ser renovado (voltar acima de todos os limites)
+	report.AlertedThreshold = previous.AlertedThreshold
+	crossed := e.SSL.CrossedThreshold(report.DaysLeft)
+	notifyExpiring := crossed > 0 && (report.AlertedThreshold == 0 || crossed < report.AlertedThreshold)
+	if crossed == 0 || notifyExpiring {
+		report.AlertedThreshold = crossed
+	}
+
+	if err := r.endpoints.UpdateSSLData(ctx, e.ID, *report); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to update SSL data")
+		return
+	}
+	e.SSLData = *report
+
+	var changes []string
+	if report.Fingerprint != previous.Fingerprint {
+		changes = certificateChanges(previous, *report)
+		record := report.CertificateRecord()
+		record.Changes = changes
+		if err := r.endpoints.AppendSSLHistory(ctx, e.ID, record); err != nil {
+			log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to append SSL history")
+		}
+	}
+	// troca de emissor é tratada como evento de segurança e alerta mesmo sem alert_on_change
+	notifyChanged := len(changes) > 0 && (previous.Issuer != report.Issuer || (e.SSL != nil && e.SSL.AlertOnChange))
+
+	// a troca sai antes do alerta de revogado/inválido: um certificado novo que também quebra o pin ou a
+	// cadeia precisa dos dois eventos
+	if notifyChanged {
+		r.dispatch(ctx, e, entities.EventSSLChanged, strings.Join(changes, "\n"))
+	}
+	if report.Status == entities.SSLRevoked && previous.Status != entities.SSLRevoked {
+		r.dispatch(ctx, e, entities.EventSSLRevoked, strings.Join(report.Problems, "\n"))
+		return
+	}
+	if report.Status == entities.SSLInvalid && previous.Status != entities.SSLInvalid {
+		r.dispatch(ctx, e, entities.EventSSLInvalid, strings.Join(report.Problems, "\n"))
+		return
+	}
+	if notifyExpiring && !report.Expired {
+		r.dispatch(ctx, e, entities.EventSSLExpiring, strings.Join(report.Problems, "\n"))
+	}
+}
+
+func (r *SSLRunner) dispatch(ctx context.Context, e *entities.Endpoint, eventType entities.EventType, message string) {
+	log.Warn().Str("endpoint", e.Name).Str("event", string(eventType)).Int("days_left", e.SSLData.DaysLeft).Msg("🔐 SSL alert")
+	if err := r.dispatcher.Dispatch(ctx, notifications.Event{Type: eventType, Endpoint: e, Message: message}); err != nil {
+		log.Error().Err(err).Str("endpoint", e.Name).Msg("Failed to dispatch SSL event")
+	}
+}
+
+// certificateChanges descreve as diferenças entre o certificado anterior e o atual
+func certificateChanges(previous, current entities.SSLData) []string {
+	if previous.Fingerprint == "" {
+		return nil
+	}
+	var changes []string
+	if previous.Issuer != current.Issuer {
+		changes = append(changes, fmt.Sprintf("emissor: %s → %s", previous.Issuer, current.Issuer))
+	}
+	if previous.SerialNumber != current.SerialNumber {
+		changes = append(changes, fmt.Sprintf("serial: %s → %s", previous.SerialNumber, current.SerialNumber))
+	}
+	if added, removed := diffStrings(previous.SANs, current.SANs); len(added) > 0 || len(removed) > 0 {
+		msg := "SANs alterados"
+		if len(added) > 0 {
+			msg += "; adicionados: " + strings.Join(added, ", ")
+		}
+		if len(removed) > 0 {
+			msg += "; removidos: " + strings.Join(removed, ", ")
+		}
+		changes = append(changes, msg)
+	}
+	if previous.SPKIFingerprint != current.SPKIFingerprint {
+		changes = append(changes, "chave pública alterada")
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "certificado reemitido com os mesmos dados")
+	}
+	return changes
+}
+
+func diffStrings(before, after []string) (added, removed []string) {
+	seen := make(map[string]bool, len(before))
+	for _, s := range before {
+		seen[s] = true
+	}
+	for _, s := range after {
+		if !seen[s] {
+			added = append(added, s)
+		}
+		delete(seen, s)
+	}
+	for _, s := range before {
+		if seen[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
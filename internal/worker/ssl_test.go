@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brunohfonseca/ratatoskr/internal/entities"
+)
+
+func TestCertificateChanges(t *testing.T) {
+	base := entities.SSLData{
+		Fingerprint:     "aa",
+		SPKIFingerprint: "k1",
+		SerialNumber:    "01",
+		Issuer:          "R3",
+		SANs:            []string{"example.com", "www.example.com"},
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(d *entities.SSLData)
+		initial bool
+		want    []string
+	}{
+		{
+			name:    "primeiro certificado visto",
+			initial: true,
+			want:    nil,
+		},
+		{
+			name:   "reemitido com os mesmos dados",
+			mutate: func(d *entities.SSLData) { d.Fingerprint = "bb" },
+			want:   []string{"certificado reemitido com os mesmos dados"},
+		},
+		{
+			name: "novo emissor e serial",
+			mutate: func(d *entities.SSLData) {
+				d.Issuer = "E1"
+				d.SerialNumber = "02"
+			},
+			want: []string{"emissor: R3 → E1", "serial: 01 → 02"},
+		},
+		{
+			name:   "SANs adicionados e removidos",
+			mutate: func(d *entities.SSLData) { d.SANs = []string{"example.com", "api.example.com"} },
+			want:   []string{"SANs alterados; adicionados: api.example.com; removidos: www.example.com"},
+		},
+		{
+			name:   "SANs reordenados não contam como mudança",
+			mutate: func(d *entities.SSLData) { d.SANs = []string{"www.example.com", "example.com"} },
+			want:   []string{"certificado reemitido com os mesmos dados"},
+		},
+		{
+			name:   "nova chave",
+			mutate: func(d *entities.SSLData) { d.SPKIFingerprint = "k2" },
+			want:   []string{"chave pública alterada"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := base
+			if tt.initial {
+				previous = entities.SSLData{}
+			}
+			current := base
+			current.SANs = append([]string(nil), base.SANs...)
+			if tt.mutate != nil {
+				tt.mutate(&current)
+			}
+			if got := certificateChanges(previous, current); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("certificateChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffStrings(t *testing.T) {
+	tests := []struct {
+		before, after  []string
+		added, removed []string
+	}{
+		{nil, nil, nil, nil},
+		{nil, []string{"a"}, []string{"a"}, nil},
+		{[]string{"a"}, nil, nil, []string{"a"}},
+		{[]string{"a", "b"}, []string{"b", "c"}, []string{"c"}, []string{"a"}},
+		{[]string{"a", "b"}, []string{"b", "a"}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		added, removed := diffStrings(tt.before, tt.after)
+		if !reflect.DeepEqual(added, tt.added) || !reflect.DeepEqual(removed, tt.removed) {
+			t.Errorf("diffStrings(%q, %q) = %q, %q; want %q, %q", tt.before, tt.after, added, removed, tt.added, tt.removed)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Job - Tarefa periódica executada pelo worker
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start executa cada job em sua própria goroutine até o contexto ser cancelado
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	log.Info().Str("job", job.Name).Dur("interval", job.Interval).Msg("⏱️ Job agendado")
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := job.Run(ctx); err != nil {
+				log.Error().Err(err).Str("job", job.Name).Msg("Erro ao executar job")
+				continue
+			}
+			log.Debug().Str("job", job.Name).Dur("duration", time.Since(start)).Msg("Job executado")
+		}
+	}
+}